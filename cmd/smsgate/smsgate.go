@@ -3,16 +3,26 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/android-sms-gateway/cli/internal/commands/auth"
+	"github.com/android-sms-gateway/cli/internal/commands/devices"
+	"github.com/android-sms-gateway/cli/internal/commands/health"
+	"github.com/android-sms-gateway/cli/internal/commands/inbox"
 	"github.com/android-sms-gateway/cli/internal/commands/logs"
 	"github.com/android-sms-gateway/cli/internal/commands/messages"
+	"github.com/android-sms-gateway/cli/internal/commands/optout"
+	"github.com/android-sms-gateway/cli/internal/commands/profiles"
+	"github.com/android-sms-gateway/cli/internal/commands/settings"
 	"github.com/android-sms-gateway/cli/internal/commands/webhooks"
 	"github.com/android-sms-gateway/cli/internal/config"
 	"github.com/android-sms-gateway/cli/internal/core/client"
 	"github.com/android-sms-gateway/cli/internal/core/codes"
 	"github.com/android-sms-gateway/cli/internal/core/output"
+	"github.com/android-sms-gateway/cli/internal/credentials"
 	"github.com/android-sms-gateway/cli/internal/utils/metadata"
 	"github.com/joho/godotenv"
+	"github.com/samber/lo"
 	"github.com/urfave/cli/v2"
 )
 
@@ -31,10 +41,30 @@ func main() {
 		os.Exit(codes.ParamsError)
 	}
 
-	cmds := make([]*cli.Command, 0, len(messages.Commands())+len(webhooks.Commands())+len(logs.Commands()))
+	cmds := make(
+		[]*cli.Command,
+		0,
+		len(messages.Commands())+
+			len(webhooks.Commands())+
+			len(inbox.Commands())+
+			len(devices.Commands())+
+			len(health.Commands())+
+			len(settings.Commands())+
+			len(logs.Commands())+
+			len(auth.Commands())+
+			len(profiles.Commands())+
+			len(optout.Commands()),
+	)
 	cmds = append(cmds, messages.Commands()...)
 	cmds = append(cmds, webhooks.Commands()...)
+	cmds = append(cmds, inbox.Commands()...)
+	cmds = append(cmds, devices.Commands()...)
+	cmds = append(cmds, health.Commands()...)
+	cmds = append(cmds, settings.Commands()...)
 	cmds = append(cmds, logs.Commands()...)
+	cmds = append(cmds, auth.Commands()...)
+	cmds = append(cmds, profiles.Commands()...)
+	cmds = append(cmds, optout.Commands()...)
 
 	app := &cli.App{
 		Name:     "smsgate",
@@ -42,6 +72,25 @@ func main() {
 		Version:  version,
 		Commands: cmds,
 		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "config",
+				DefaultText: "~/.config/smsgate/config.yaml",
+				Usage:       "Path to the config file with connection profiles",
+				Category:    categoryConfiguration,
+				EnvVars: []string{
+					"ASG_CONFIG",
+				},
+			},
+			&cli.StringFlag{
+				Name:        "profile",
+				DefaultText: "current profile from the config file",
+				Usage:       "Connection profile name",
+				Category:    categoryConfiguration,
+				Aliases:     []string{"P"},
+				EnvVars: []string{
+					"ASG_PROFILE",
+				},
+			},
 			&cli.StringFlag{
 				Name:        "endpoint",
 				DefaultText: config.DefaultEndpoint,
@@ -61,7 +110,6 @@ func main() {
 				EnvVars: []string{
 					"ASG_USERNAME",
 				},
-				Required: true,
 			},
 			&cli.StringFlag{
 				Name:     "password",
@@ -71,13 +119,29 @@ func main() {
 				EnvVars: []string{
 					"ASG_PASSWORD",
 				},
-				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "credentials-passphrase",
+				Usage:    "Passphrase protecting credentials saved by `auth login`",
+				Category: categoryConfiguration,
+				EnvVars: []string{
+					"ASG_CREDENTIALS_PASSPHRASE",
+				},
+			},
+			&cli.StringFlag{
+				Name:     "token",
+				Aliases:  []string{"t"},
+				Usage:    "JWT access token, replaces username and password",
+				Category: categoryConfiguration,
+				EnvVars: []string{
+					"ASG_TOKEN",
+				},
 			},
 
 			&cli.StringFlag{
 				Name:     "format",
 				Category: categoryOutput,
-				Usage:    "Output format. Supported: text, json, raw, table",
+				Usage:    "Output format. Supported: text, json, raw, table, csv",
 				Required: false,
 				Value:    string(output.Text),
 				Aliases:  []string{"f"},
@@ -89,20 +153,7 @@ func main() {
 				Email: "support@sms-gate.app",
 			},
 		},
-		Before: func(c *cli.Context) error {
-			renderer, err := output.New(output.Format(c.String("format")))
-			if err != nil {
-				return fmt.Errorf("failed to create renderer: %w", err)
-			}
-
-			c.App.Metadata[metadata.RendererKey] = renderer
-			c.App.Metadata[metadata.ClientKey] = client.New(
-				c.String("username"),
-				c.String("password"),
-				c.String("endpoint"),
-			)
-			return nil
-		},
+		Before: before,
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -110,3 +161,143 @@ func main() {
 		os.Exit(codes.ParamsError)
 	}
 }
+
+// before resolves connection settings with the following precedence:
+// flags, environment variables, the selected profile, defaults.
+func before(c *cli.Context) error {
+	setupCode := setupExitCode(c.Args().Slice())
+
+	configPath, err := config.ResolvePath(c.String("config"))
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("failed to resolve config path: %s", err.Error()), setupCode)
+	}
+
+	profile, err := loadProfile(configPath, c.String("profile"))
+	if err != nil {
+		return cli.Exit(err.Error(), setupCode)
+	}
+
+	renderer, err := output.New(output.Format(withProfile(c, "format", profile.Format)))
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("failed to create renderer: %s", err.Error()), setupCode)
+	}
+
+	c.App.Metadata[metadata.RendererKey] = renderer
+	c.App.Metadata[metadata.ProfileKey] = profile
+	c.App.Metadata[metadata.EndpointKey] = withProfile(c, "endpoint", profile.Endpoint)
+
+	if isLocalCommand(c.Args().Slice()) {
+		return nil
+	}
+
+	username := withProfile(c, "username", profile.Username)
+	password := withProfile(c, "password", profile.Password)
+	token := withProfile(c, "token", profile.Token)
+	// the credentials of the highest-priority source decide the auth mode,
+	// so a profile token doesn't override username and password given as flags or env
+	basicSource := max(
+		settingSource(c, "username", "ASG_USERNAME", profile.Username),
+		settingSource(c, "password", "ASG_PASSWORD", profile.Password),
+	)
+	if settingSource(c, "token", "ASG_TOKEN", profile.Token) < basicSource {
+		token = ""
+	}
+	if token == "" && (username == "" || password == "") {
+		// fall back to credentials saved by `auth login`
+		store := credentials.NewStore(filepath.Dir(configPath), c.String("credentials-passphrase"))
+		creds, ok, storeErr := store.Get(profile.Name)
+		if storeErr != nil {
+			return cli.Exit(fmt.Sprintf("failed to load saved credentials: %s", storeErr.Error()), codes.ParamsError)
+		}
+		if ok && (username == "" || username == creds.Username) {
+			username = creds.Username
+			password = lo.CoalesceOrEmpty(password, creds.Password)
+		}
+	}
+	if token == "" && (username == "" || password == "") {
+		return cli.Exit("Either token or username and password are required", codes.ParamsError)
+	}
+
+	c.App.Metadata[metadata.ClientKey] = client.New(
+		username,
+		password,
+		token,
+		metadata.GetEndpoint(c.App.Metadata),
+	)
+	return nil
+}
+
+func loadProfile(path, name string) (config.Profile, error) {
+	file, err := config.Load(path)
+	if err != nil {
+		return config.Profile{}, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	profile, err := file.Profile(name)
+	if err != nil {
+		return config.Profile{}, fmt.Errorf("failed to select profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// withProfile returns the flag value if it was set via command line or env,
+// otherwise the profile value, falling back to the flag default.
+func withProfile(c *cli.Context, name, value string) string {
+	if c.IsSet(name) || value == "" {
+		return c.String(name)
+	}
+
+	return value
+}
+
+// Sources of a setting, in ascending precedence.
+const (
+	sourceNone = iota
+	sourceProfile
+	sourceEnv
+	sourceFlag
+)
+
+// settingSource returns where the value of the setting comes from. A flag with
+// the same value as its environment variable counts as set via env.
+func settingSource(c *cli.Context, name, envVar, profileValue string) int {
+	switch {
+	case c.IsSet(name) && c.String(name) != os.Getenv(envVar):
+		return sourceFlag
+	case c.IsSet(name):
+		return sourceEnv
+	case profileValue != "":
+		return sourceProfile
+	default:
+		return sourceNone
+	}
+}
+
+// setupExitCode returns the exit code of the setup failures of the command. The
+// health probe reports them as UNKNOWN, so monitoring doesn't read a broken
+// configuration as a failing gateway.
+func setupExitCode(args []string) int {
+	if len(args) > 0 && args[0] == "health" {
+		return codes.HealthUnknown
+	}
+
+	return codes.ParamsError
+}
+
+// isLocalCommand reports whether the command works without credentials.
+// The health probe connects to the server anonymously.
+func isLocalCommand(args []string) bool {
+	if len(args) == 0 {
+		return true
+	}
+
+	switch args[0] {
+	case "help", "h", "config", "profiles", "health", "optout":
+		return true
+	case "auth":
+		return len(args) < 2 || args[1] == "login" || args[1] == "logout"
+	default:
+		return false
+	}
+}
@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+func Commands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Category: categoryAuth,
+			Name:     "auth",
+			Usage:    "Manage authentication",
+			Subcommands: []*cli.Command{
+				loginCmd(),
+				logoutCmd(),
+				{
+					Name:  "token",
+					Usage: "Manage JWT access tokens",
+					Subcommands: []*cli.Command{
+						issueCmd(),
+						revokeCmd(),
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,7 @@
+package auth
+
+import "errors"
+
+var (
+	ErrNotTerminal = errors.New("stdin is not a terminal, use --password-stdin to pipe the password")
+)
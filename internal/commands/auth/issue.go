@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/utils/metadata"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/samber/lo"
+	"github.com/urfave/cli/v2"
+)
+
+func issueCmd() *cli.Command {
+	return &cli.Command{
+		Name:    "issue",
+		Aliases: []string{"i", "new"},
+		Usage:   "Issue a new access token",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:     "scopes",
+				Aliases:  []string{"scope", "s"},
+				Usage:    "Token scopes, e.g. messages:send,messages:read",
+				Required: true,
+			},
+			&cli.DurationFlag{
+				Name:        "ttl",
+				Usage:       "Token time to live (duration, e.g. 1h)",
+				DefaultText: "server default",
+			},
+		},
+		Before: issueBefore,
+		Action: issueAction,
+	}
+}
+
+func issueBefore(c *cli.Context) error {
+	scopes := parseScopes(c.StringSlice("scopes"))
+	if len(scopes) == 0 {
+		return cli.Exit("At least one scope is required", codes.ParamsError)
+	}
+
+	ttl := c.Duration("ttl")
+	if ttl < 0 {
+		return cli.Exit("TTL must be positive", codes.ParamsError)
+	}
+	if ttl%time.Second != 0 {
+		return cli.Exit("TTL must be a whole number of seconds", codes.ParamsError)
+	}
+
+	return nil
+}
+
+func issueAction(c *cli.Context) error {
+	client := metadata.GetClient(c.App.Metadata)
+	renderer := metadata.GetRenderer(c.App.Metadata)
+
+	req := smsgateway.TokenRequest{
+		Scopes: parseScopes(c.StringSlice("scopes")),
+		TTL:    uint64(c.Duration("ttl") / time.Second), //nolint:gosec // validated
+	}
+
+	res, err := client.GenerateToken(c.Context, req)
+	if err != nil {
+		return cli.Exit(err.Error(), codes.ClientError)
+	}
+
+	b, err := renderer.Token(res)
+	if err != nil {
+		return cli.Exit(err.Error(), codes.OutputError)
+	}
+	fmt.Fprintln(os.Stdout, b)
+
+	return nil
+}
+
+func parseScopes(src []string) []string {
+	scopes := lo.Map(src, func(s string, _ int) string { return strings.TrimSpace(s) })
+	return lo.Uniq(lo.Compact(scopes))
+}
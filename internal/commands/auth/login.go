@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/credentials"
+	"github.com/android-sms-gateway/cli/internal/utils/metadata"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+func loginCmd() *cli.Command {
+	return &cli.Command{
+		Category: categoryAuth,
+		Name:     "login",
+		Usage: "Save username and password to the encrypted local credential store; " +
+			"the password is prompted without echo",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "password-stdin",
+				Usage: "Read the password from stdin instead of prompting",
+				Value: false,
+			},
+		},
+		Action: loginAction,
+	}
+}
+
+func loginAction(c *cli.Context) error {
+	profile := metadata.GetProfile(c.App.Metadata)
+	renderer := metadata.GetRenderer(c.App.Metadata)
+	passwordStdin := c.Bool("password-stdin")
+
+	username := c.String("username")
+	if username == "" {
+		username = profile.Username
+	}
+	if username == "" {
+		if passwordStdin || !term.IsTerminal(int(os.Stdin.Fd())) { //nolint:gosec // fd fits into int
+			return cli.Exit("Username is required, use --username or ASG_USERNAME", codes.ParamsError)
+		}
+
+		var err error
+		if username, err = promptLine("Username: "); err != nil {
+			return cli.Exit(err.Error(), codes.ParamsError)
+		}
+	}
+
+	password, err := readPassword(passwordStdin)
+	if err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+	if username == "" || password == "" {
+		return cli.Exit("Username and password must not be empty", codes.ParamsError)
+	}
+
+	store, err := newStore(c)
+	if err != nil {
+		return err
+	}
+
+	if setErr := store.Set(profile.Name, credentials.Credentials{Username: username, Password: password}); setErr != nil {
+		return cli.Exit(fmt.Sprintf("failed to save credentials: %s", setErr.Error()), codes.OutputError)
+	}
+	fmt.Fprintf(os.Stderr, "Credentials saved to %s\n", store.Path())
+
+	b, err := renderer.Success()
+	if err != nil {
+		return cli.Exit(err.Error(), codes.OutputError)
+	}
+	if b != "" {
+		fmt.Fprintln(os.Stdout, b)
+	}
+	return nil
+}
+
+func readPassword(fromStdin bool) (string, error) {
+	if fromStdin {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("read password: %w", err)
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+
+	fd := int(os.Stdin.Fd()) //nolint:gosec // fd fits into int
+	if !term.IsTerminal(fd) {
+		return "", ErrNotTerminal
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	b, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("read password: %w", err)
+	}
+
+	return string(b), nil
+}
+
+func promptLine(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read input: %w", err)
+	}
+
+	return strings.TrimSpace(line), nil
+}
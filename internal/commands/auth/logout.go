@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/utils/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+func logoutCmd() *cli.Command {
+	return &cli.Command{
+		Category: categoryAuth,
+		Name:     "logout",
+		Usage:    "Delete saved credentials of the current profile",
+		Action: func(c *cli.Context) error {
+			profile := metadata.GetProfile(c.App.Metadata)
+			renderer := metadata.GetRenderer(c.App.Metadata)
+
+			store, err := newStore(c)
+			if err != nil {
+				return err
+			}
+
+			deleted, err := store.Delete(profile.Name)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to delete credentials: %s", err.Error()), codes.OutputError)
+			}
+			if !deleted {
+				fmt.Fprintln(os.Stderr, "No saved credentials found")
+			}
+
+			b, err := renderer.Success()
+			if err != nil {
+				return cli.Exit(err.Error(), codes.OutputError)
+			}
+			if b != "" {
+				fmt.Fprintln(os.Stdout, b)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/utils/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+func revokeCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "revoke",
+		Aliases:   []string{"r", "rm"},
+		Usage:     "Revoke an access token",
+		Args:      true,
+		ArgsUsage: "JTI (token ID)",
+		Action: func(c *cli.Context) error {
+			jti := c.Args().Get(0)
+			if jti == "" {
+				return cli.Exit("Token ID is empty", codes.ParamsError)
+			}
+
+			client := metadata.GetClient(c.App.Metadata)
+			renderer := metadata.GetRenderer(c.App.Metadata)
+
+			if err := client.RevokeToken(c.Context, jti); err != nil {
+				return cli.Exit(err.Error(), codes.ClientError)
+			}
+
+			b, err := renderer.Success()
+			if err != nil {
+				return cli.Exit(err.Error(), codes.OutputError)
+			}
+			if b != "" {
+				fmt.Fprintln(os.Stdout, b)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"path/filepath"
+
+	"github.com/android-sms-gateway/cli/internal/config"
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/credentials"
+	"github.com/urfave/cli/v2"
+)
+
+// newStore opens the credentials store located next to the config file.
+func newStore(c *cli.Context) (*credentials.Store, error) {
+	path, err := config.ResolvePath(c.String("config"))
+	if err != nil {
+		return nil, cli.Exit(err.Error(), codes.ParamsError)
+	}
+
+	return credentials.NewStore(filepath.Dir(path), c.String("credentials-passphrase")), nil
+}
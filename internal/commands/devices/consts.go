@@ -0,0 +1,5 @@
+package devices
+
+const (
+	categoryDevices = "Devices"
+)
@@ -0,0 +1,20 @@
+package devices
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+func Commands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Category: categoryDevices,
+			Name:     "devices",
+			Aliases:  []string{"device", "dev"},
+			Usage:    "Manage devices",
+			Subcommands: []*cli.Command{
+				listCmd(),
+				removeCmd(),
+			},
+		},
+	}
+}
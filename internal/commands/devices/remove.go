@@ -0,0 +1,44 @@
+package devices
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/utils/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+func removeCmd() *cli.Command {
+	return &cli.Command{
+		Category:  categoryDevices,
+		Name:      "remove",
+		Aliases:   []string{"rm", "delete", "d"},
+		Usage:     "Remove device",
+		Args:      true,
+		ArgsUsage: "ID",
+		Action: func(c *cli.Context) error {
+			id := c.Args().Get(0)
+			if id == "" {
+				return cli.Exit("ID is empty", codes.ParamsError)
+			}
+
+			client := metadata.GetClient(c.App.Metadata)
+			renderer := metadata.GetRenderer(c.App.Metadata)
+
+			err := client.DeleteDevice(c.Context, id)
+			if err != nil {
+				return cli.Exit(err.Error(), codes.ClientError)
+			}
+
+			b, err := renderer.Success()
+			if err != nil {
+				return cli.Exit(err.Error(), codes.OutputError)
+			}
+			if b != "" {
+				fmt.Fprintln(os.Stdout, b)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package flags
+
+import (
+	"math"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	categoryDataMessage = "Data Message"
+
+	defaultDataPort = 53739
+)
+
+// Data returns the flags selecting data messages and their destination port.
+func Data() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:     "data",
+			Category: categoryDataMessage,
+			Usage:    "Send data message instead of text, content should be base64 encoded",
+			Value:    false,
+		},
+		&cli.UintFlag{
+			Name:     "data-port",
+			Category: categoryDataMessage,
+			Aliases:  []string{"dataPort"},
+			Usage:    "Destination port for data message (1 to 65535)",
+			Value:    defaultDataPort,
+		},
+	}
+}
+
+// DataPort returns the --data-port value and whether it is a valid port.
+func DataPort(c *cli.Context) (uint16, bool) {
+	port := c.Uint("data-port")
+	if port < 1 || port > math.MaxUint16 {
+		return 0, false
+	}
+
+	return uint16(port), true
+}
@@ -0,0 +1,30 @@
+package flags
+
+import (
+	"github.com/android-sms-gateway/cli/internal/encryption"
+	"github.com/urfave/cli/v2"
+)
+
+const categoryEncryption = "Encryption"
+
+// Encryption returns the end-to-end encryption flags.
+func Encryption() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     "passphrase",
+			Category: categoryEncryption,
+			Usage:    "End-to-end encryption passphrase shared with the device",
+			EnvVars:  []string{"ASG_PASSPHRASE"},
+		},
+	}
+}
+
+// NewEncryptor returns the encryptor for the --passphrase flag or nil when encryption is disabled.
+func NewEncryptor(c *cli.Context) *encryption.Encryptor {
+	passphrase := c.String("passphrase")
+	if passphrase == "" {
+		return nil
+	}
+
+	return encryption.New(passphrase)
+}
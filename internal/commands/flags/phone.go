@@ -0,0 +1,34 @@
+package flags
+
+import (
+	"fmt"
+
+	"github.com/android-sms-gateway/cli/internal/phones"
+	"github.com/urfave/cli/v2"
+)
+
+const categoryPhone = "Phone Numbers"
+
+// Phone returns the flags controlling the normalization of phone numbers.
+func Phone() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     "default-region",
+			Category: categoryPhone,
+			Usage: "Region of numbers without the country code, e.g. US; " +
+				"numbers are converted to E.164 and impossible numbers are rejected",
+			EnvVars: []string{"ASG_DEFAULT_REGION"},
+			Value:   "",
+		},
+	}
+}
+
+// NewPhoneNormalizer returns the phone number normalizer for the --default-region flag.
+func NewPhoneNormalizer(c *cli.Context) (*phones.Normalizer, error) {
+	normalizer, err := phones.New(c.String("default-region"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationFailed, err)
+	}
+
+	return normalizer, nil
+}
@@ -0,0 +1,70 @@
+package flags
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/android-sms-gateway/cli/internal/core/retry"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	categoryRetry = "Retry"
+
+	defaultRetryBackoff = time.Second
+	defaultRetryJitter  = 0.2
+)
+
+// Retry returns the flags of the policy repeating sends failed with transient errors.
+func Retry() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:     "retries",
+			Category: categoryRetry,
+			Usage:    "Number of retries after timeouts, connection resets, 429 and 5xx responses",
+			Value:    0,
+		},
+		&cli.DurationFlag{
+			Name:     "retry-backoff",
+			Category: categoryRetry,
+			Usage:    "Delay before the first retry, doubled for every next one",
+			Value:    defaultRetryBackoff,
+		},
+		&cli.Float64Flag{
+			Name:     "retry-jitter",
+			Category: categoryRetry,
+			Usage:    "Random spread of the retry delay as a fraction from 0 to 1",
+			Value:    defaultRetryJitter,
+		},
+	}
+}
+
+// NewRetryPolicy returns the retry policy for the --retries, --retry-backoff and --retry-jitter flags.
+func NewRetryPolicy(c *cli.Context) (retry.Policy, error) {
+	policy := retry.Policy{
+		Retries:          c.Int("retries"),
+		Backoff:          c.Duration("retry-backoff"),
+		Jitter:           c.Float64("retry-jitter"),
+		RateLimitRetries: 0,
+	}
+
+	if policy.Retries < 0 {
+		return retry.Policy{}, fmt.Errorf("%w: retries must not be negative: %d", ErrValidationFailed, policy.Retries)
+	}
+	if policy.Backoff <= 0 {
+		return retry.Policy{}, fmt.Errorf(
+			"%w: retry backoff must be positive: %s",
+			ErrValidationFailed,
+			policy.Backoff.String(),
+		)
+	}
+	if policy.Jitter < 0 || policy.Jitter > 1 {
+		return retry.Policy{}, fmt.Errorf(
+			"%w: retry jitter must be between 0 and 1: %g",
+			ErrValidationFailed,
+			policy.Jitter,
+		)
+	}
+
+	return policy, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/android-sms-gateway/cli/internal/utils/metadata"
 	"github.com/android-sms-gateway/client-go/smsgateway"
 	"github.com/samber/lo"
 	"github.com/urfave/cli/v2"
@@ -99,20 +100,33 @@ type SendFlags struct {
 func NewSendFlags(c *cli.Context) (*SendFlags, error) {
 	const maxSIMNumber = 255
 
+	// values not set via flags or env fall back to the active profile
+	defaults := metadata.GetProfile(c.App.Metadata).Send
+
 	fl := &SendFlags{
-		DeviceID:            c.String("device-id"),
-		DeliveryReport:      c.Bool("delivery-report"),
-		ValidUntil:          c.Timestamp("valid-until"),
-		SkipPhoneValidation: c.Bool("skip-phone-validation"),
-		DeviceActiveWithin:  c.Uint("device-active-within"),
-		ScheduleAt:          c.Timestamp("schedule-at"),
+		DeviceID:       withDefault(c, "device-id", c.String("device-id"), lo.EmptyableToPtr(defaults.DeviceID)),
+		DeliveryReport: withDefault(c, "delivery-report", c.Bool("delivery-report"), defaults.DeliveryReport),
+		ValidUntil:     c.Timestamp("valid-until"),
+		SkipPhoneValidation: withDefault(
+			c,
+			"skip-phone-validation",
+			c.Bool("skip-phone-validation"),
+			defaults.SkipPhoneValidation,
+		),
+		DeviceActiveWithin: withDefault(
+			c,
+			"device-active-within",
+			c.Uint("device-active-within"),
+			defaults.DeviceActiveWithin,
+		),
+		ScheduleAt: c.Timestamp("schedule-at"),
 
 		SimNumber: nil,
 		Priority:  smsgateway.PriorityDefault,
 		TTL:       nil,
 	}
 
-	simNumber := c.Uint("sim-number")
+	simNumber := withDefault(c, "sim-number", c.Uint("sim-number"), defaults.SimNumber)
 	if simNumber > maxSIMNumber {
 		return nil, fmt.Errorf(
 			"%w: sim number must be between 0 and %d: %d",
@@ -123,7 +137,7 @@ func NewSendFlags(c *cli.Context) (*SendFlags, error) {
 	}
 	fl.SimNumber = lo.EmptyableToPtr(uint8(simNumber))
 
-	ttl := c.Duration("ttl")
+	ttl := withDefault(c, "ttl", c.Duration("ttl"), defaults.TTL)
 	if ttl < 0 {
 		return nil, fmt.Errorf("%w: TTL must be positive: %s", ErrValidationFailed, ttl.String())
 	}
@@ -152,9 +166,7 @@ func NewSendFlags(c *cli.Context) (*SendFlags, error) {
 	}
 	fl.ScheduleAt = scheduleAt
 
-	priority := c.Int(
-		"priority",
-	)
+	priority := withDefault(c, "priority", c.Int("priority"), defaults.Priority)
 	if priority < int(smsgateway.PriorityMinimum) ||
 		priority > int(smsgateway.PriorityMaximum) {
 		return nil, fmt.Errorf(
@@ -205,3 +217,13 @@ func (s SendFlags) Option() []smsgateway.SendOption {
 
 	return options
 }
+
+// withDefault returns value when the flag was set explicitly (via command line or env)
+// and the profile default otherwise, if there is one.
+func withDefault[T any](c *cli.Context, name string, value T, fallback *T) T {
+	if c.IsSet(name) || fallback == nil {
+		return value
+	}
+
+	return *fallback
+}
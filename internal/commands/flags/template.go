@@ -0,0 +1,114 @@
+package flags
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/android-sms-gateway/cli/internal/templates"
+	"github.com/android-sms-gateway/cli/internal/utils/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+const categoryTemplate = "Template"
+
+// Template returns the flags selecting a message template.
+func Template() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     "template",
+			Category: categoryTemplate,
+			Usage:    "Message template in Go text/template syntax, e.g. 'Hello {{.FirstName}}'",
+		},
+		&cli.StringFlag{
+			Name:     "template-file",
+			Category: categoryTemplate,
+			Usage:    "Read message template from file",
+		},
+		&cli.StringFlag{
+			Name:     "template-name",
+			Category: categoryTemplate,
+			Usage:    "Use a named template from the config profile",
+		},
+	}
+}
+
+// HasTemplate reports whether any of the template flags is set.
+func HasTemplate(c *cli.Context) bool {
+	return c.IsSet("template") || c.IsSet("template-file") || c.IsSet("template-name")
+}
+
+// NewTemplate parses the template selected by the template flags.
+// It returns nil when no template is requested.
+func NewTemplate(c *cli.Context) (*templates.Template, error) {
+	var (
+		text    string
+		sources int
+	)
+
+	if c.IsSet("template") {
+		sources++
+		text = c.String("template")
+	}
+	if c.IsSet("template-file") {
+		sources++
+		b, err := os.ReadFile(c.String("template-file"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to read template file: %w", ErrValidationFailed, err)
+		}
+		text = strings.TrimRight(string(b), "\r\n")
+	}
+	if c.IsSet("template-name") {
+		sources++
+		name := c.String("template-name")
+		named, ok := metadata.GetProfile(c.App.Metadata).Templates[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: template %q not found in the config profile", ErrValidationFailed, name)
+		}
+		text = named
+	}
+
+	switch {
+	case sources == 0:
+		return nil, nil //nolint:nilnil // template is not requested
+	case sources > 1:
+		return nil, fmt.Errorf(
+			"%w: --template, --template-file and --template-name are mutually exclusive",
+			ErrValidationFailed,
+		)
+	}
+
+	tmpl, err := templates.Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationFailed, err)
+	}
+
+	return tmpl, nil
+}
+
+// Vars is a repeatable key=value flag value. Unlike StringSliceFlag it does not
+// split values on commas, so message text can be passed as is.
+type Vars map[string]string
+
+// Set implements flag.Value.
+func (v Vars) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return fmt.Errorf("%w: %q, expected key=value", ErrValidationFailed, value)
+	}
+
+	v[key] = val
+
+	return nil
+}
+
+// String implements flag.Value.
+func (v Vars) String() string {
+	pairs := make([]string, 0, len(v))
+	for key, val := range v {
+		pairs = append(pairs, key+"="+val)
+	}
+
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,8 @@
+package health
+
+import "errors"
+
+var (
+	ErrInvalidEndpoint    = errors.New("invalid endpoint")
+	ErrUnexpectedResponse = errors.New("unexpected health response")
+)
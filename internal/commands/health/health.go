@@ -0,0 +1,109 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/utils/metadata"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	categorySystem = "System"
+
+	// requestTimeout limits the health request, a hanging gateway is critical.
+	requestTimeout = 30 * time.Second
+	// maxResponseSize limits the health report read from the server.
+	maxResponseSize = 1 << 20
+)
+
+func Commands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Category: categorySystem,
+			Name:     "health",
+			Usage:    "Check gateway health, the exit code follows the Nagios convention",
+			Action:   healthAction,
+			OnUsageError: func(_ *cli.Context, err error, _ bool) error {
+				return cli.Exit(err.Error(), codes.HealthUnknown)
+			},
+		},
+	}
+}
+
+func healthAction(c *cli.Context) error {
+	renderer := metadata.GetRenderer(c.App.Metadata)
+
+	target, err := healthURL(metadata.GetEndpoint(c.App.Metadata))
+	if err != nil {
+		return cli.Exit(err.Error(), codes.HealthUnknown)
+	}
+
+	res, err := checkHealth(c.Context, target)
+	if err != nil {
+		return cli.Exit(err.Error(), codes.HealthCritical)
+	}
+
+	s, err := renderer.Health(res)
+	if err != nil {
+		return cli.Exit(err.Error(), codes.HealthUnknown)
+	}
+	fmt.Fprintln(os.Stdout, s)
+
+	switch res.Status {
+	case smsgateway.HealthStatusPass:
+		return nil
+	case smsgateway.HealthStatusWarn:
+		return cli.Exit("Health status: warn", codes.HealthWarning)
+	case smsgateway.HealthStatusFail:
+		return cli.Exit("Health status: fail", codes.HealthCritical)
+	default:
+		return cli.Exit(fmt.Sprintf("Unknown health status: %q", res.Status), codes.HealthUnknown)
+	}
+}
+
+// healthURL returns the URL of the health report of the endpoint. A malformed
+// endpoint is a setup failure, not a failing gateway.
+func healthURL(endpoint string) (string, error) {
+	parsed, err := url.Parse(strings.TrimRight(endpoint, "/") + "/health")
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "", fmt.Errorf("%w %q: use an absolute http or https URL", ErrInvalidEndpoint, endpoint)
+	}
+
+	return parsed.String(), nil
+}
+
+// checkHealth requests the health report without credentials. A failing
+// gateway answers 503 with the report, so the body is read for any status code.
+func checkHealth(ctx context.Context, target string) (smsgateway.HealthResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	var res smsgateway.HealthResponse
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return res, fmt.Errorf("check health: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return res, fmt.Errorf("check health: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if decodeErr := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&res); decodeErr != nil ||
+		res.Status == "" {
+		return res, fmt.Errorf("%w: status code %d", ErrUnexpectedResponse, resp.StatusCode)
+	}
+
+	return res, nil
+}
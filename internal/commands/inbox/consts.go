@@ -0,0 +1,5 @@
+package inbox
+
+const (
+	categoryInbox = "Inbox"
+)
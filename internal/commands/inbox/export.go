@@ -0,0 +1,59 @@
+package inbox
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/flags"
+	"github.com/android-sms-gateway/cli/internal/utils/metadata"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/urfave/cli/v2"
+)
+
+func exportCmd() *cli.Command {
+	f := []cli.Flag{
+		&cli.StringFlag{
+			Name:     "device-id",
+			Aliases:  []string{"device"},
+			Usage:    "Device ID to export received messages from",
+			Required: true,
+		},
+	}
+	f = append(f, flags.Period()...)
+
+	return &cli.Command{
+		Category: categoryInbox,
+		Name:     "export",
+		Usage:    "Re-deliver messages received in the time range to the sms:received webhooks",
+		Flags:    f,
+		Before:   flags.ValidatePeriod,
+		Action:   exportAction,
+	}
+}
+
+func exportAction(c *cli.Context) error {
+	period := flags.ParsePeriodFlags(c)
+
+	client := metadata.GetClient(c.App.Metadata)
+	renderer := metadata.GetRenderer(c.App.Metadata)
+
+	err := client.ExportInbox(c.Context, smsgateway.MessagesExportRequest{
+		DeviceID: c.String("device-id"),
+		Since:    *period.From,
+		Until:    *period.To,
+	})
+	if err != nil {
+		return cli.Exit(err.Error(), codes.ClientError)
+	}
+
+	b, err := renderer.Success()
+	if err != nil {
+		return cli.Exit(err.Error(), codes.OutputError)
+	}
+	if b != "" {
+		fmt.Fprintln(os.Stdout, b)
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package inbox
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+func Commands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Category: categoryInbox,
+			Name:     "inbox",
+			Usage:    "Manage received messages",
+			Subcommands: []*cli.Command{
+				exportCmd(),
+			},
+		},
+	}
+}
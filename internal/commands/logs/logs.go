@@ -29,17 +29,7 @@ func logsCmd() *cli.Command {
 }
 
 func logsBefore(c *cli.Context) error {
-	period := flags.ParsePeriodFlags(c)
-
-	if period.From == nil || period.To == nil {
-		return cli.Exit("From and To dates are required", codes.ParamsError)
-	}
-
-	if period.From.After(*period.To) {
-		return cli.Exit("From date must be less than or equal to To date", codes.ParamsError)
-	}
-
-	return nil
+	return flags.ValidatePeriod(c)
 }
 
 func logsAction(c *cli.Context) error {
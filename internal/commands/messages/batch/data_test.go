@@ -0,0 +1,115 @@
+package batch_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchSend_DataMessages(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.write(t, "input.csv",
+		"Phone,Message,Payload,Port\n"+
+			"+12025550123,Hello,,\n"+
+			"+12025550124,,AQID,\n"+
+			"+12025550125,,BAUG,8080\n",
+	)
+
+	require.NoError(t, f.run(t,
+		"--map", "phone=Phone,text=Message,data=Payload,data_port=Port",
+		"--data-port", "1234",
+		path,
+	))
+
+	requests, _ := f.server.captured()
+	require.Len(t, requests, 3)
+
+	assert.Equal(t, map[string]any{"text": "Hello"}, requests["+12025550123"]["textMessage"])
+	assert.NotContains(t, requests["+12025550123"], "dataMessage")
+
+	assert.Equal(t, map[string]any{"data": "AQID", "port": float64(1234)}, requests["+12025550124"]["dataMessage"])
+	assert.NotContains(t, requests["+12025550124"], "textMessage")
+
+	assert.Equal(t, map[string]any{"data": "BAUG", "port": float64(8080)}, requests["+12025550125"]["dataMessage"])
+}
+
+func TestBatchSend_InvalidDataRows(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.write(t, "input.csv",
+		"Phone,Payload,Port\n"+
+			"+12025550123,,\n"+
+			"+12025550124,not base64!,\n"+
+			"+12025550125,AQID,70000\n"+
+			"+12025550126,AQID,0\n"+
+			"+12025550127,AQID,\n",
+	)
+
+	err := f.run(t,
+		"--map", "phone=Phone,data=Payload,data_port=Port",
+		"--data",
+		"--validate-only",
+		path,
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "row 2: validation failed: data is empty")
+	assert.Contains(t, err.Error(), "row 3: validation failed: invalid base64 data")
+	assert.Contains(t, err.Error(), "row 4: validation failed: data_port must be between 1 and 65535: 70000")
+	assert.Contains(t, err.Error(), "row 5: validation failed: data_port must be between 1 and 65535: 0")
+	assert.NotContains(t, err.Error(), "row 6")
+}
+
+func TestBatchSend_DataMapping(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{
+			name:        "data mode without data column",
+			args:        []string{"--map", "phone=Phone,text=Message", "--data"},
+			expectedErr: "data messages require map data=<column>",
+		},
+		{
+			name:        "data mode with text column",
+			args:        []string{"--map", "phone=Phone,text=Message,data=Payload", "--data"},
+			expectedErr: "data messages can't be combined with text=<column>",
+		},
+		{
+			name:        "data column with template",
+			args:        []string{"--map", "phone=Phone,data=Payload", "--template", "Hello"},
+			expectedErr: "map data=<column> can't be combined with a message template",
+		},
+		{
+			name:        "data port without data column",
+			args:        []string{"--map", "phone=Phone,text=Message,data_port=Port"},
+			expectedErr: "map data_port=<column> requires data=<column>",
+		},
+		{
+			name:        "invalid default data port",
+			args:        []string{"--map", "phone=Phone,data=Payload", "--data-port", "0"},
+			expectedErr: "data port must be between 1 and 65535",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path, cmd, ok := newBatchSendFixture(t)
+			require.True(t, ok)
+
+			ctx := newContext(t, cmd, append(tt.args, path))
+
+			err := cmd.Before(ctx)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
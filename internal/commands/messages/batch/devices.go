@@ -0,0 +1,316 @@
+package batch
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/android-sms-gateway/cli/internal/commands/messages/batch/mappings"
+	"github.com/android-sms-gateway/cli/internal/core/ratelimit"
+	"github.com/samber/lo"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/time/rate"
+)
+
+const (
+	strategyRoundRobin    = "round-robin"
+	strategyWeighted      = "weighted"
+	strategyStickyByPhone = "sticky-by-phone"
+)
+
+// target is the device and SIM a row is sent with. Empty values leave the
+// choice to the flags or the server.
+type target struct {
+	DeviceID  string
+	SimNumber *uint8
+}
+
+// key returns the comparable form of the target for the per-device counts.
+func (t target) key() deviceKey {
+	return deviceKey{DeviceID: t.DeviceID, SimNumber: lo.FromPtr(t.SimNumber)}
+}
+
+// deviceKey identifies a device and SIM pair, a zero SIM number is the default SIM.
+type deviceKey struct {
+	DeviceID  string
+	SimNumber uint8
+}
+
+func (k deviceKey) String() string {
+	switch {
+	case k.SimNumber == 0:
+		return k.DeviceID
+	case k.DeviceID == "":
+		return fmt.Sprintf("SIM %d", k.SimNumber)
+	default:
+		return fmt.Sprintf("%s SIM %d", k.DeviceID, k.SimNumber)
+	}
+}
+
+func compareDeviceKeys(a, b deviceKey) int {
+	return cmp.Or(strings.Compare(a.DeviceID, b.DeviceID), cmp.Compare(a.SimNumber, b.SimNumber))
+}
+
+// distributor spreads the rows without their own device and SIM across the
+// device and SIM pairs. A nil distributor leaves the rows as they are.
+type distributor struct {
+	strategy string
+	targets  []target
+	weights  []int
+
+	// next is the index of the next round-robin target.
+	next int
+	// current are the smooth weighted round-robin counters of the targets.
+	current []int
+}
+
+// newDistributor parses --devices, --sims and --strategy. It returns nil when
+// the rows are not distributed.
+func newDistributor(c *cli.Context) (*distributor, error) {
+	strategy := c.String("strategy")
+	switch strategy {
+	case strategyRoundRobin, strategyWeighted, strategyStickyByPhone:
+	default:
+		return nil, fmt.Errorf(
+			"%w: strategy must be one of: round-robin, weighted, sticky-by-phone",
+			ErrValidationFailed,
+		)
+	}
+
+	devices, weights, err := parseDevices(c.String("devices"))
+	if err != nil {
+		return nil, err
+	}
+	sims, err := parseSims(c.String("sims"))
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(devices) > 0 && c.IsSet("device-id"):
+		return nil, fmt.Errorf("%w: --devices can't be combined with --device-id", ErrValidationFailed)
+	case len(sims) > 0 && c.IsSet("sim-number"):
+		return nil, fmt.Errorf("%w: --sims can't be combined with --sim-number", ErrValidationFailed)
+	case strategy != strategyWeighted && lo.SomeBy(weights, func(w int) bool { return w != 1 }):
+		return nil, fmt.Errorf("%w: device weights require --strategy weighted", ErrValidationFailed)
+	case len(devices) == 0 && len(sims) == 0:
+		if c.IsSet("strategy") {
+			return nil, fmt.Errorf("%w: --strategy requires --devices or --sims", ErrValidationFailed)
+		}
+		return nil, nil //nolint:nilnil // rows are not distributed
+	}
+
+	if len(devices) == 0 {
+		devices, weights = []string{""}, []int{1}
+	}
+	if len(sims) == 0 {
+		sims = []*uint8{nil}
+	}
+
+	// the devices alternate first, so consecutive rows go to different phones
+	d := &distributor{
+		strategy: strategy,
+		targets:  make([]target, 0, len(devices)*len(sims)),
+		weights:  make([]int, 0, len(devices)*len(sims)),
+		next:     0,
+		current:  make([]int, len(devices)*len(sims)),
+	}
+	for _, sim := range sims {
+		for i, device := range devices {
+			d.targets = append(d.targets, target{DeviceID: device, SimNumber: sim})
+			d.weights = append(d.weights, weights[i])
+		}
+	}
+
+	return d, nil
+}
+
+// parseDevices parses the comma-separated device IDs with optional :weight suffixes.
+func parseDevices(raw string) ([]string, []int, error) {
+	devices := make([]string, 0)
+	weights := make([]int, 0)
+	for part := range strings.SplitSeq(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		device, weight := part, 1
+		if id, rawWeight, ok := strings.Cut(part, ":"); ok {
+			w, err := strconv.Atoi(strings.TrimSpace(rawWeight))
+			if err != nil || w < 1 {
+				return nil, nil, fmt.Errorf("%w: device %q, weight must be a positive integer", ErrValidationFailed, part)
+			}
+			device, weight = strings.TrimSpace(id), w
+		}
+		if device == "" {
+			return nil, nil, fmt.Errorf("%w: device %q, ID must not be empty", ErrValidationFailed, part)
+		}
+		if lo.Contains(devices, device) {
+			return nil, nil, fmt.Errorf("%w: duplicate device %q", ErrValidationFailed, device)
+		}
+
+		devices = append(devices, device)
+		weights = append(weights, weight)
+	}
+
+	return devices, weights, nil
+}
+
+// parseSims parses the comma-separated one-based SIM numbers.
+func parseSims(raw string) ([]*uint8, error) {
+	sims := make([]*uint8, 0)
+	seen := map[uint64]struct{}{}
+	for part := range strings.SplitSeq(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		sim, err := strconv.ParseUint(part, 10, 8)
+		if err != nil || sim == 0 {
+			return nil, fmt.Errorf("%w: SIM number %q, must be between 1 and 255", ErrValidationFailed, part)
+		}
+		if _, ok := seen[sim]; ok {
+			return nil, fmt.Errorf("%w: duplicate SIM number %d", ErrValidationFailed, sim)
+		}
+		seen[sim] = struct{}{}
+
+		sims = append(sims, lo.ToPtr(uint8(sim)))
+	}
+
+	return sims, nil
+}
+
+// Assign returns the target of the row. Rows with their own device or SIM
+// keep them and get an empty target.
+func (d *distributor) Assign(row mappings.SendRow) target {
+	if d == nil || row.DeviceID != "" || row.SimNumber != nil {
+		return target{DeviceID: "", SimNumber: nil}
+	}
+
+	var index int
+	switch d.strategy {
+	case strategyWeighted:
+		index = d.nextWeighted()
+	case strategyStickyByPhone:
+		index = stickyIndex(row, len(d.targets))
+	default:
+		index = d.next
+		d.next = (d.next + 1) % len(d.targets)
+	}
+
+	return d.targets[index]
+}
+
+// nextWeighted picks the target with the smooth weighted round-robin, which
+// interleaves the targets instead of sending runs of rows to the heaviest one.
+func (d *distributor) nextWeighted() int {
+	total, best := 0, 0
+	for i, weight := range d.weights {
+		d.current[i] += weight
+		total += weight
+		if d.current[i] > d.current[best] {
+			best = i
+		}
+	}
+	d.current[best] -= total
+
+	return best
+}
+
+// stickyIndex maps the first recipient of the row to a target, so a number is
+// always messaged from the same device and SIM.
+func stickyIndex(row mappings.SendRow, count int) int {
+	h := fnv.New32a()
+	if len(row.Phones) > 0 {
+		_, _ = h.Write([]byte(row.Phones[0]))
+	}
+
+	return int(h.Sum32() % uint32(count)) //nolint:gosec // count is a small positive number
+}
+
+// deviceLimits caps the concurrent sends and the send rate of every device.
+// A nil value sets no limits.
+type deviceLimits struct {
+	concurrency int
+	rate        rate.Limit
+
+	mu      sync.Mutex
+	devices map[string]*deviceLimit
+}
+
+type deviceLimit struct {
+	slots   chan struct{}
+	limiter *ratelimit.Limiter
+}
+
+// newDeviceLimits parses --device-concurrency and --device-rate. It returns
+// nil when the devices are not limited.
+func newDeviceLimits(c *cli.Context) (*deviceLimits, error) {
+	concurrency := c.Int("device-concurrency")
+	if concurrency < 0 {
+		return nil, fmt.Errorf("%w: device concurrency must not be negative", ErrValidationFailed)
+	}
+
+	limit, err := parseRate(c.String("device-rate"))
+	if err != nil {
+		return nil, fmt.Errorf("device rate: %w", err)
+	}
+
+	if concurrency == 0 && limit == rate.Inf {
+		return nil, nil //nolint:nilnil // devices are not limited
+	}
+
+	return &deviceLimits{
+		concurrency: concurrency,
+		rate:        limit,
+		mu:          sync.Mutex{},
+		devices:     map[string]*deviceLimit{},
+	}, nil
+}
+
+// Acquire waits for a free send slot of the device. It returns the context
+// throttled by the device rate and the function releasing the slot. Sends
+// without a device ID are not limited.
+func (l *deviceLimits) Acquire(ctx context.Context, deviceID string) (context.Context, func(), error) {
+	if l == nil || deviceID == "" {
+		return ctx, func() {}, nil
+	}
+
+	device := l.device(deviceID)
+	if device.slots == nil {
+		return ratelimit.WithLimiter(ctx, device.limiter), func() {}, nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx, func() {}, fmt.Errorf("wait for device %s: %w", deviceID, ctx.Err())
+	case device.slots <- struct{}{}:
+	}
+
+	return ratelimit.WithLimiter(ctx, device.limiter), func() { <-device.slots }, nil
+}
+
+func (l *deviceLimits) device(deviceID string) *deviceLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	device, ok := l.devices[deviceID]
+	if !ok {
+		device = &deviceLimit{slots: nil, limiter: nil}
+		if l.concurrency > 0 {
+			device.slots = make(chan struct{}, l.concurrency)
+		}
+		if l.rate != rate.Inf {
+			device.limiter = ratelimit.New(l.rate, 1)
+		}
+		l.devices[deviceID] = device
+	}
+
+	return device
+}
@@ -0,0 +1,147 @@
+package batch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchSend_DevicesRoundRobin(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.write(t, "input.csv", "Phone,Message,Device\n"+
+		"+12025550121,Hello,\n"+
+		"+12025550122,Hello,\n"+
+		"+12025550123,Hello,own\n"+
+		"+12025550124,Hello,\n"+
+		"+12025550125,Hello,\n"+
+		"+12025550126,Hello,\n")
+	reportPath := f.path("report.csv")
+
+	require.NoError(t, f.run(t,
+		"--map", "phone=Phone,text=Message,device_id=Device",
+		"--devices", "d1,d2",
+		"--sims", "1,2",
+		"--concurrency", "1",
+		"--report", reportPath,
+		path,
+	))
+
+	// the devices alternate first, the row with its own device is not distributed
+	targets, _ := f.server.devices()
+	assert.Equal(t, []string{"d1/1", "d2/1", "own/0", "d1/2", "d2/2", "d1/1"}, targets)
+
+	rows := readReport(t, reportPath)
+	require.Len(t, rows, 6)
+	assert.Equal(t, "d2", rows[1]["device_id"])
+	assert.Equal(t, "1", rows[1]["sim_number"])
+	assert.Equal(t, "own", rows[2]["device_id"])
+	assert.Empty(t, rows[2]["sim_number"])
+}
+
+func TestBatchSend_DevicesWeighted(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.writePhones(
+		t,
+		"+12025550121", "+12025550122", "+12025550123", "+12025550124",
+		"+12025550125", "+12025550126", "+12025550127", "+12025550128",
+	)
+
+	require.NoError(t, f.run(t,
+		"--map", "phone=Phone,text=Message",
+		"--devices", "d1:3,d2",
+		"--strategy", "weighted",
+		"--concurrency", "1",
+		path,
+	))
+
+	// the heavier device is interleaved with the lighter one
+	targets, _ := f.server.devices()
+	assert.Equal(t, []string{"d1/0", "d1/0", "d2/0", "d1/0", "d1/0", "d1/0", "d2/0", "d1/0"}, targets)
+}
+
+func TestBatchSend_DevicesStickyByPhone(t *testing.T) {
+	t.Parallel()
+
+	phones := []string{"+12025550121", "+12025550122", "+12025550123", "+12025550124", "+12025550125"}
+	f := newSendFixture(t, &sendServer{})
+	path := f.writePhones(t, append(phones, phones...)...)
+
+	require.NoError(t, f.run(t,
+		"--map", "phone=Phone,text=Message",
+		"--devices", "d1,d2,d3",
+		"--strategy", "sticky-by-phone",
+		"--concurrency", "1",
+		path,
+	))
+
+	// every number is messaged from the same device both times
+	targets, _ := f.server.devices()
+	require.Len(t, targets, 10)
+	assert.Equal(t, targets[:5], targets[5:])
+}
+
+func TestBatchSend_DeviceConcurrency(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{delay: 20 * time.Millisecond})
+	path := f.writePhones(
+		t,
+		"+12025550121", "+12025550122", "+12025550123", "+12025550124",
+		"+12025550125", "+12025550126", "+12025550127", "+12025550128",
+	)
+
+	require.NoError(t, f.run(t,
+		"--map", "phone=Phone,text=Message",
+		"--devices", "d1,d2",
+		"--device-concurrency", "1",
+		"--concurrency", "8",
+		path,
+	))
+
+	targets, peak := f.server.devices()
+	assert.Len(t, targets, 8)
+	assert.Equal(t, map[string]int{"d1": 1, "d2": 1}, peak)
+}
+
+func TestBatchSend_InvalidDevices(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"unknown strategy", []string{"--devices", "d1", "--strategy", "random"}, "strategy must be one of"},
+		{"strategy without devices", []string{"--strategy", "weighted"}, "--strategy requires --devices or --sims"},
+		{"weights without weighted", []string{"--devices", "d1:2,d2"}, "device weights require --strategy weighted"},
+		{"invalid weight", []string{"--devices", "d1:0", "--strategy", "weighted"}, "weight must be a positive integer"},
+		{"duplicate device", []string{"--devices", "d1,d1"}, `duplicate device "d1"`},
+		{"invalid SIM", []string{"--sims", "0"}, "must be between 1 and 255"},
+		{"device ID flag", []string{"--devices", "d1", "--device-id", "d2"}, "can't be combined with --device-id"},
+		{"SIM number flag", []string{"--sims", "1", "--sim-number", "2"}, "can't be combined with --sim-number"},
+		{"negative concurrency", []string{"--device-concurrency", "-1"}, "device concurrency must not be negative"},
+		{"invalid rate", []string{"--device-rate", "fast"}, "device rate"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			path, cmd, ok := newBatchSendFixture(t)
+			require.True(t, ok)
+
+			args := append([]string{"--map", "phone=Phone,text=Message"}, tc.args...)
+			ctx := newContext(t, cmd, append(args, path))
+
+			err := cmd.Before(ctx)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.want)
+		})
+	}
+}
@@ -4,4 +4,8 @@ import "errors"
 
 var (
 	ErrValidationFailed = errors.New("validation failed")
+	ErrJournal          = errors.New("journal")
+	ErrReadInput        = errors.New("read input")
+	ErrDuplicate        = errors.New("duplicate")
+	ErrSuppressed       = errors.New("suppressed")
 )
@@ -0,0 +1,217 @@
+package batch_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/cli/internal/commands/messages"
+	"github.com/android-sms-gateway/cli/internal/core/client"
+	"github.com/android-sms-gateway/cli/internal/core/output"
+	"github.com/android-sms-gateway/cli/internal/utils/metadata"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+// sendServer is a mock gateway recording the sent messages: the phone numbers
+// and IDs in order, the device and SIM as "device/sim", the bodies and the
+// skipPhoneValidation query parameter by the first phone number, and the highest
+// number of concurrent sends per device.
+type sendServer struct {
+	// delay is the time every send takes.
+	delay time.Duration
+	fail  map[string]bool
+	// transient is the number of 503 responses before the phone succeeds.
+	transient map[string]int
+
+	mu       sync.Mutex
+	phones   []string
+	ids      []string
+	targets  []string
+	requests map[string]map[string]any
+	queries  map[string]string
+	inFlight map[string]int
+	peak     map[string]int
+}
+
+func (s *sendServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID           string   `json:"id"`
+		PhoneNumbers []string `json:"phoneNumbers"`
+		DeviceID     string   `json:"deviceId"`
+		SimNumber    int      `json:"simNumber"`
+	}
+	var body map[string]any
+	raw, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = errors.Join(json.Unmarshal(raw, &req), json.Unmarshal(raw, &body))
+	}
+	if err != nil || len(req.PhoneNumbers) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	phone := req.PhoneNumbers[0]
+
+	s.mu.Lock()
+	if s.requests == nil {
+		s.requests = map[string]map[string]any{}
+		s.queries = map[string]string{}
+		s.inFlight = map[string]int{}
+		s.peak = map[string]int{}
+	}
+	s.phones = append(s.phones, req.PhoneNumbers...)
+	s.ids = append(s.ids, req.ID)
+	s.targets = append(s.targets, fmt.Sprintf("%s/%d", req.DeviceID, req.SimNumber))
+	s.requests[phone] = body
+	s.queries[phone] = r.URL.Query().Get("skipPhoneValidation")
+	s.inFlight[req.DeviceID]++
+	s.peak[req.DeviceID] = max(s.peak[req.DeviceID], s.inFlight[req.DeviceID])
+	fail := s.fail[phone]
+	unavailable := s.transient[phone] > 0
+	if unavailable {
+		s.transient[phone]--
+	}
+	s.mu.Unlock()
+
+	time.Sleep(s.delay)
+
+	s.mu.Lock()
+	s.inFlight[req.DeviceID]--
+	s.mu.Unlock()
+
+	if unavailable {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	if fail {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]any{"id": req.ID, "state": "Pending"})
+}
+
+func (s *sendServer) sent() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string{}, s.phones...)
+}
+
+// devices returns the targets of the sent messages and the peak concurrency per device.
+func (s *sendServer) devices() ([]string, map[string]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string{}, s.targets...), s.peak
+}
+
+// reset forgets the sent messages and the failures.
+func (s *sendServer) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.phones, s.ids, s.targets = nil, nil, nil
+	s.requests, s.queries, s.inFlight, s.peak = nil, nil, nil, nil
+	s.fail, s.transient = nil, nil
+}
+
+// sentIDs returns the message IDs of the sent messages in order.
+func (s *sendServer) sentIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string{}, s.ids...)
+}
+
+// captured returns the bodies and the skipPhoneValidation query parameters.
+func (s *sendServer) captured() (map[string]map[string]any, map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests, s.queries
+}
+
+// sendFixture is the batch send command sending to a mock gateway, with a
+// temporary directory for its input and output files.
+type sendFixture struct {
+	cmd    *cli.Command
+	server *sendServer
+	url    string
+	dir    string
+}
+
+// newSendFixture starts the mock gateway with the server.
+func newSendFixture(t *testing.T, server *sendServer) *sendFixture {
+	t.Helper()
+
+	cmd, ok := findBatchSendCommand(messages.Commands())
+	require.True(t, ok)
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	return &sendFixture{cmd: cmd, server: server, url: httpServer.URL, dir: t.TempDir()}
+}
+
+// path returns the path of the file in the fixture directory.
+func (f *sendFixture) path(name string) string {
+	return filepath.Join(f.dir, name)
+}
+
+// write writes the file to the fixture directory and returns its path.
+func (f *sendFixture) write(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := f.path(name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+// writePhones writes an input file with a row per phone number.
+func (f *sendFixture) writePhones(t *testing.T, phones ...string) string {
+	t.Helper()
+
+	var b strings.Builder
+	b.WriteString("Phone,Message\n")
+	for _, phone := range phones {
+		b.WriteString(phone + ",Hello\n")
+	}
+
+	return f.write(t, "input.csv", b.String())
+}
+
+// context returns the context of the command with the arguments, sending to the mock gateway.
+func (f *sendFixture) context(t *testing.T, args ...string) *cli.Context {
+	t.Helper()
+
+	ctx := newContext(t, f.cmd, args)
+	ctx.App.Metadata = map[string]any{
+		metadata.ClientKey:   client.New("user", "pass", "", f.url),
+		metadata.RendererKey: output.NewJSONOutput(),
+	}
+
+	return ctx
+}
+
+// run checks the arguments and runs the command.
+func (f *sendFixture) run(t *testing.T, args ...string) error {
+	t.Helper()
+
+	ctx := f.context(t, args...)
+	require.NoError(t, f.cmd.Before(ctx))
+
+	return f.cmd.Action(ctx)
+}
@@ -0,0 +1,94 @@
+package batch
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/android-sms-gateway/cli/pkg/io/tabular"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	formatCSV    = "csv"
+	formatXLSX   = "xlsx"
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+
+	// stdinPath reads the input from stdin.
+	stdinPath = "-"
+)
+
+// inputFormat returns the --input-format value or the format of the file extension.
+func inputFormat(c *cli.Context) (string, error) {
+	if format := strings.ToLower(strings.TrimSpace(c.String("input-format"))); format != "" {
+		switch format {
+		case formatCSV, formatXLSX, formatJSON, formatNDJSON:
+			return format, nil
+		default:
+			return "", fmt.Errorf(
+				"%w: unsupported input format %q; use csv, xlsx, json or ndjson",
+				ErrValidationFailed,
+				format,
+			)
+		}
+	}
+
+	path := c.Args().Get(0)
+	if path == stdinPath {
+		return "", fmt.Errorf("%w: reading from stdin requires --input-format", ErrValidationFailed)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		return formatCSV, nil
+	case ".xlsx", ".xlsm":
+		return formatXLSX, nil
+	case ".json":
+		return formatJSON, nil
+	case ".ndjson", ".jsonl":
+		return formatNDJSON, nil
+	default:
+		return "", fmt.Errorf(
+			"%w: unsupported file extension %q; use .csv, .xlsx, .json or .ndjson, or set --input-format",
+			ErrValidationFailed,
+			ext,
+		)
+	}
+}
+
+func newTabularReader(c *cli.Context) (tabular.StreamReader, error) {
+	format, err := inputFormat(c)
+	if err != nil {
+		return nil, err
+	}
+
+	path := c.Args().Get(0)
+	var input io.Reader
+	if path == stdinPath {
+		input = os.Stdin
+	}
+
+	switch format {
+	case formatXLSX:
+		return tabular.NewXLSXReader(tabular.XLSXConfig{
+			Path:      path,
+			Input:     input,
+			Sheet:     c.String("sheet"),
+			HasHeader: c.Bool("header"),
+		}), nil
+	case formatJSON:
+		return tabular.NewJSONReader(tabular.JSONConfig{Path: path, Input: input}), nil
+	case formatNDJSON:
+		return tabular.NewNDJSONReader(tabular.JSONConfig{Path: path, Input: input}), nil
+	default:
+		return tabular.NewCSVReader(tabular.CSVConfig{
+			Path:      path,
+			Input:     input,
+			Delimiter: []rune(c.String("delimiter"))[0],
+			HasHeader: c.Bool("header"),
+		}), nil
+	}
+}
@@ -0,0 +1,111 @@
+package batch_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/android-sms-gateway/cli/internal/commands/messages"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchSend_JSONInput(t *testing.T) {
+	t.Parallel()
+
+	inputs := map[string]string{
+		"input.json": `[{"contact":{"mobile":"+12025550123"},"body":"Hello"},` +
+			`{"contact":{"mobile":"+12025550124"},"body":"Hi"}]`,
+		"input.ndjson": "{\"contact\":{\"mobile\":\"+12025550123\"},\"body\":\"Hello\"}\n" +
+			"{\"contact\":{\"mobile\":\"+12025550124\"},\"body\":\"Hi\"}\n",
+		// the format flag wins over the extension
+		"input.txt": "{\"contact\":{\"mobile\":\"+12025550123\"},\"body\":\"Hello\"}\n" +
+			"{\"contact\":{\"mobile\":\"+12025550124\"},\"body\":\"Hi\"}\n",
+	}
+
+	for name, content := range inputs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			f := newSendFixture(t, &sendServer{})
+			path := f.write(t, name, content)
+
+			args := []string{"--map", "phone=contact.mobile,text=body"}
+			if filepath.Ext(name) == ".txt" {
+				args = append(args, "--input-format", "ndjson")
+			}
+
+			require.NoError(t, f.run(t, append(args, path)...))
+
+			requests, _ := f.server.captured()
+			require.Len(t, requests, 2)
+			assert.Equal(t, map[string]any{"text": "Hello"}, requests["+12025550123"]["textMessage"])
+			assert.Equal(t, map[string]any{"text": "Hi"}, requests["+12025550124"]["textMessage"])
+		})
+	}
+}
+
+func TestBatchSend_JSONInputOptionalKeys(t *testing.T) {
+	t.Parallel()
+
+	// the objects carry different optional keys
+	f := newSendFixture(t, &sendServer{})
+	path := f.write(t, "input.ndjson",
+		"{\"phone\":\"+12025550123\",\"body\":\"Hello\",\"ttl\":3600,\"priority\":100}\n"+
+			"{\"phone\":\"+12025550124\",\"body\":\"Hi\",\"report\":false}\n"+
+			"{\"phone\":\"+12025550125\",\"body\":\"Hey\"}\n",
+	)
+
+	require.NoError(t, f.run(t,
+		"--map", "phone=phone,text=body,ttl=ttl,priority=priority,delivery_report=report",
+		path,
+	))
+
+	requests, _ := f.server.captured()
+	require.Len(t, requests, 3)
+	assert.InDelta(t, 3600, requests["+12025550123"]["ttl"], 0)
+	assert.InDelta(t, 100, requests["+12025550123"]["priority"], 0)
+	assert.Equal(t, false, requests["+12025550124"]["withDeliveryReport"])
+	assert.NotContains(t, requests["+12025550125"], "ttl")
+	assert.NotContains(t, requests["+12025550125"], "priority")
+}
+
+func TestBatchSend_InputFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{
+			name:        "stdin without format",
+			args:        []string{"-"},
+			expectedErr: "reading from stdin requires --input-format",
+		},
+		{
+			name:        "unsupported format",
+			args:        []string{"--input-format", "xml", "-"},
+			expectedErr: `unsupported input format "xml"`,
+		},
+		{
+			name:        "unsupported extension",
+			args:        []string{"input.xml"},
+			expectedErr: `unsupported file extension ".xml"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, ok := findBatchSendCommand(messages.Commands())
+			require.True(t, ok)
+
+			ctx := newContext(t, cmd, append([]string{"--map", "phone=Phone,text=Message"}, tt.args...))
+
+			err := cmd.Before(ctx)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/android-sms-gateway/cli/internal/commands/messages/batch/mappings"
+)
+
+const (
+	journalStatusEnqueued = "enqueued"
+	journalStatusFailed   = "failed"
+
+	journalFileMode = 0o600
+)
+
+// journalEntry is a single line of the journal file.
+type journalEntry struct {
+	Row        int       `json:"row"`
+	Hash       string    `json:"hash"`
+	Identifier string    `json:"id"`
+	Status     string    `json:"status"`
+	Attempts   int       `json:"attempts"`
+	Error      string    `json:"error,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+type journalKey struct {
+	row  int
+	hash string
+}
+
+// journal records the outcome of every sent row as JSON lines so an interrupted
+// batch can be resumed without sending the enqueued rows again.
+// A nil journal records nothing.
+type journal struct {
+	file *os.File
+	// enqueued maps the rows enqueued by previous runs to their identifiers.
+	enqueued map[journalKey]string
+	// failed maps the rows failed by previous runs to their identifiers, so a
+	// message the server accepted before the failure isn't sent twice on resume.
+	failed map[journalKey]string
+}
+
+// openJournal opens the journal file for appending. With resume the rows enqueued
+// by previous runs are loaded, otherwise an existing non-empty journal is rejected
+// to avoid mixing the outcomes of different batches.
+func openJournal(path string, resume bool) (*journal, error) {
+	if path == "" {
+		return nil, nil //nolint:nilnil // journal is disabled
+	}
+
+	enqueued := map[journalKey]string{}
+	failed := map[journalKey]string{}
+	if resume {
+		entries, err := readJournal(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			key := journalKey{row: entry.Row, hash: entry.Hash}
+			switch entry.Status {
+			case journalStatusEnqueued:
+				enqueued[key] = entry.Identifier
+			case journalStatusFailed:
+				failed[key] = entry.Identifier
+			}
+		}
+	} else if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+		return nil, fmt.Errorf("%w: journal %s already exists; use --resume to continue the batch", ErrJournal, path)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, journalFileMode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJournal, err)
+	}
+
+	return &journal{file: f, enqueued: enqueued, failed: failed}, nil
+}
+
+// readJournal reads the journal entries. A missing file is an empty journal,
+// an incomplete last line left by an interrupted write is ignored.
+func readJournal(path string) ([]journalEntry, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJournal, err)
+	}
+
+	var entries []journalEntry
+	reader := bufio.NewReader(bytes.NewReader(b))
+	for lineNumber := 1; ; lineNumber++ {
+		line, readErr := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var entry journalEntry
+			if jsonErr := json.Unmarshal(line, &entry); jsonErr != nil {
+				if errors.Is(readErr, io.EOF) {
+					break
+				}
+				return nil, fmt.Errorf("%w: line %d: %w", ErrJournal, lineNumber, jsonErr)
+			}
+			entries = append(entries, entry)
+		}
+		if readErr != nil {
+			break
+		}
+	}
+
+	return entries, nil
+}
+
+// EnqueuedCount returns the number of rows enqueued by previous runs.
+func (j *journal) EnqueuedCount() int {
+	if j == nil {
+		return 0
+	}
+
+	return len(j.enqueued)
+}
+
+// Enqueued returns the identifier of the row if it was enqueued by a previous run.
+func (j *journal) Enqueued(row mappings.SendRow) (string, bool) {
+	if j == nil {
+		return "", false
+	}
+
+	identifier, ok := j.enqueued[journalKey{row: row.RowNumber, hash: rowHash(row)}]
+	return identifier, ok
+}
+
+// Failed returns the identifier the row was sent with by a previous run that
+// failed, or an empty string.
+func (j *journal) Failed(row mappings.SendRow) string {
+	if j == nil {
+		return ""
+	}
+
+	return j.failed[journalKey{row: row.RowNumber, hash: rowHash(row)}]
+}
+
+// Record appends the outcome of the row to the journal.
+func (j *journal) Record(result batchRowResult) error {
+	if j == nil {
+		return nil
+	}
+
+	entry := journalEntry{
+		Row:        result.Row.RowNumber,
+		Hash:       rowHash(result.Row),
+		Identifier: result.Identifier,
+		Status:     journalStatusEnqueued,
+		Attempts:   result.Attempts,
+		Error:      "",
+		Time:       time.Now().UTC(),
+	}
+	if result.Error != nil {
+		entry.Status = journalStatusFailed
+		entry.Error = result.Error.Error()
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrJournal, err)
+	}
+	if _, writeErr := j.file.Write(append(b, '\n')); writeErr != nil {
+		return fmt.Errorf("%w: %w", ErrJournal, writeErr)
+	}
+
+	return nil
+}
+
+func (j *journal) Close() error {
+	if j == nil {
+		return nil
+	}
+
+	if err := j.file.Close(); err != nil {
+		return fmt.Errorf("%w: %w", ErrJournal, err)
+	}
+
+	return nil
+}
+
+// rowHash identifies the row content, so an edited row is sent again on resume.
+func rowHash(row mappings.SendRow) string {
+	row.RowNumber = 0
+
+	b, _ := json.Marshal(row) //nolint:errchkjson // SendRow contains only plain fields
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:])
+}
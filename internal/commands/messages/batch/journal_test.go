@@ -0,0 +1,164 @@
+package batch_test
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchSend_JournalResume(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{fail: map[string]bool{"+12025550124": true}})
+	path := f.write(t, "input.csv", "Phone,Message\n+12025550123,Hello\n+12025550124,Hello\n+12025550125,Hello\n")
+	journalPath := f.path("journal.jsonl")
+
+	args := []string{
+		"--map", "phone=Phone,text=Message",
+		"--concurrency", "1",
+		"--continue-on-error",
+		"--journal", journalPath,
+	}
+
+	require.Error(t, f.run(t, append(args, path)...))
+	assert.ElementsMatch(t, []string{"+12025550123", "+12025550124", "+12025550125"}, f.server.sent())
+
+	journal, err := os.ReadFile(journalPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(journal)), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, string(journal), `"status":"failed"`)
+
+	// a second run without --resume must not send anything again
+	err = f.run(t, append(args, path)...)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "use --resume")
+	assert.Len(t, f.server.sent(), 3)
+
+	var failed struct {
+		ID string `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &failed))
+	require.NotEmpty(t, failed.ID)
+
+	// only the failed row is sent on resume, with the ID of the failed attempt
+	f.server.reset()
+	require.NoError(t, f.run(t, append(args, "--resume", path)...))
+	assert.Equal(t, []string{"+12025550124"}, f.server.sent())
+	assert.Equal(t, []string{failed.ID}, f.server.sentIDs())
+
+	// nothing is left to send
+	f.server.reset()
+	require.NoError(t, f.run(t, append(args, "--resume", path)...))
+	assert.Empty(t, f.server.sent())
+}
+
+func TestBatchSend_JournalResumeEditedRow(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.write(t, "input.csv", "Phone,Message\n+12025550123,Hello\n")
+	args := []string{"--map", "phone=Phone,text=Message", "--journal", f.path("journal.jsonl"), "--resume", path}
+
+	require.NoError(t, f.run(t, args...))
+
+	// the row content changed, so it is not considered enqueued anymore
+	f.write(t, "input.csv", "Phone,Message\n+12025550123,Hello again\n")
+
+	require.NoError(t, f.run(t, args...))
+	assert.Equal(t, []string{"+12025550123", "+12025550123"}, f.server.sent())
+}
+
+func TestBatchSend_JournalResumePastSchedule(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{fail: map[string]bool{"+12025550124": true}})
+	scheduleAt := time.Now().Add(2 * time.Second).Truncate(time.Second)
+	path := f.write(t, "input.csv", "Phone,Message,Schedule\n"+
+		"+12025550123,Hello,"+scheduleAt.Format(time.RFC3339)+"\n"+
+		"+12025550124,Hello,"+scheduleAt.Format(time.RFC3339)+"\n")
+	reportPath := f.path("report.csv")
+
+	args := []string{
+		"--map", "phone=Phone,text=Message,schedule_at=Schedule",
+		"--stream",
+		"--concurrency", "1",
+		"--continue-on-error",
+		"--journal", f.path("journal.jsonl"),
+		"--resume",
+		"--report", reportPath,
+		path,
+	}
+
+	require.Error(t, f.run(t, args...))
+
+	time.Sleep(time.Until(scheduleAt) + 10*time.Millisecond)
+
+	// the enqueued row is resumed although its schedule has passed, the failed
+	// row can't be sent anymore
+	require.Error(t, f.run(t, args...))
+
+	rows := readReport(t, reportPath)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "resumed", rows[0]["status"])
+	assert.Equal(t, "failed", rows[1]["status"])
+	assert.Contains(t, rows[1]["error"], "schedule_at must be in the future")
+	assert.Len(t, f.server.sent(), 2)
+}
+
+func TestBatchSend_ResumeWithoutJournal(t *testing.T) {
+	t.Parallel()
+
+	path, cmd, ok := newBatchSendFixture(t)
+	require.True(t, ok)
+
+	ctx := newContext(t, cmd, []string{
+		"--map", "phone=Phone,text=Message",
+		"--resume",
+		path,
+	})
+
+	err := cmd.Before(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "resume requires --journal")
+}
+
+func TestBatchSend_Retries(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{
+		fail:      map[string]bool{"+12025550124": true},
+		transient: map[string]int{"+12025550123": 2},
+	})
+	path := f.writePhones(t, "+12025550123", "+12025550124")
+	journalPath := f.path("journal.jsonl")
+
+	require.Error(t, f.run(t,
+		"--map", "phone=Phone,text=Message",
+		"--continue-on-error",
+		"--retries", "3",
+		"--retry-backoff", "1ms",
+		"--journal", journalPath,
+		path,
+	))
+
+	// validation errors are not retried
+	assert.ElementsMatch(t, []string{"+12025550123", "+12025550123", "+12025550123", "+12025550124"}, f.server.sent())
+
+	journal, err := os.ReadFile(journalPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(journal), `"row":2,`)
+	for _, line := range strings.Split(strings.TrimSpace(string(journal)), "\n") {
+		switch {
+		case strings.Contains(line, `"row":2,`):
+			assert.Contains(t, line, `"status":"enqueued","attempts":3`)
+		case strings.Contains(line, `"row":3,`):
+			assert.Contains(t, line, `"status":"failed","attempts":1`)
+		}
+	}
+}
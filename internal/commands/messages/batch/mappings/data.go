@@ -0,0 +1,51 @@
+package mappings
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"github.com/android-sms-gateway/cli/pkg/io/tabular"
+)
+
+// parseContent validates the message content of the row. A row with data,
+// or any row when data messages are the default, is a data message.
+func parseContent(record tabular.Record, mapping map[string]string, opts Options, row *SendRow) error {
+	rawPort, hasPort, err := lookup(record, mapping, "data_port")
+	if err != nil {
+		return err
+	}
+
+	if row.Data == "" && !opts.Data {
+		if row.Text == "" {
+			return fmt.Errorf("%w: text is empty", ErrValidationFailed)
+		}
+		if hasPort {
+			return fmt.Errorf("%w: data_port is set for a text message", ErrValidationFailed)
+		}
+		return nil
+	}
+
+	if row.Data == "" {
+		return fmt.Errorf("%w: data is empty", ErrValidationFailed)
+	}
+	if row.Text != "" {
+		return fmt.Errorf("%w: text and data are mutually exclusive", ErrValidationFailed)
+	}
+	if _, decErr := base64.StdEncoding.DecodeString(row.Data); decErr != nil {
+		if _, rawErr := base64.RawStdEncoding.DecodeString(row.Data); rawErr != nil {
+			return fmt.Errorf("%w: invalid base64 data", ErrValidationFailed)
+		}
+	}
+
+	row.DataPort = opts.DataPort
+	if hasPort {
+		port, parseErr := strconv.ParseUint(rawPort, 10, 16)
+		if parseErr != nil || port == 0 {
+			return fmt.Errorf("%w: data_port must be between 1 and 65535: %s", ErrValidationFailed, rawPort)
+		}
+		row.DataPort = uint16(port)
+	}
+
+	return nil
+}
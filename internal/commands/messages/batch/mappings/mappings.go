@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/android-sms-gateway/cli/internal/templates"
 	"github.com/android-sms-gateway/cli/pkg/io/tabular"
 	"github.com/samber/lo"
 )
@@ -23,6 +24,14 @@ func ParseColumnMapping(raw string) (map[string]string, error) {
 		"device_id":  {},
 		"sim_number": {},
 		"priority":   {},
+		"data":       {},
+		"data_port":  {},
+
+		"ttl":                   {},
+		"valid_until":           {},
+		"schedule_at":           {},
+		"delivery_report":       {},
+		"skip_phone_validation": {},
 	}
 	result := map[string]string{}
 	for part := range strings.SplitSeq(raw, ",") {
@@ -58,35 +67,25 @@ func ParseColumnMapping(raw string) (map[string]string, error) {
 	return result, nil
 }
 
-func MapAndValidateRows(records []tabular.Record, mapping map[string]string) ([]SendRow, []string) {
-	rows := make([]SendRow, 0, len(records))
-	errs := make([]string, 0)
+// MapRecord maps the record to validated send rows: a single row, or a row per
+// recipient when the recipients are split.
+func MapRecord(record tabular.Record, mapping map[string]string, opts Options) ([]SendRow, error) {
+	row, err := mapRow(record, mapping, opts)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, record := range records {
-		row, err := mapRow(record, mapping)
-		if err != nil {
-			errs = append(errs, fmt.Sprintf("row %d: %v", record.RowNumber, err))
-			continue
-		}
-		rows = append(rows, row)
+	if opts.SplitRecipients {
+		return splitRecipients(row), nil
 	}
 
-	return rows, errs
+	return []SendRow{row}, nil
 }
 
 func parsePriority(record tabular.Record, mapping map[string]string) (*int8, error) {
-	column, ok := mapping["priority"]
-	if !ok {
-		return nil, nil //nolint:nilnil // value is not provided
-	}
-
-	value, exists := record.Values[column]
-	if !exists {
-		return nil, fmt.Errorf("%w: invalid priority mapping: column %q not found", ErrMappingParseFailed, column)
-	}
-	raw := strings.TrimSpace(value)
-	if raw == "" {
-		return nil, nil //nolint:nilnil // value is not provided
+	raw, ok, err := lookup(record, mapping, "priority")
+	if err != nil || !ok {
+		return nil, err
 	}
 
 	priority, err := strconv.ParseInt(raw, 10, 8)
@@ -97,22 +96,50 @@ func parsePriority(record tabular.Record, mapping map[string]string) (*int8, err
 	return lo.ToPtr(int8(priority)), nil
 }
 
-func mapRow(record tabular.Record, mapping map[string]string) (SendRow, error) {
+// renderText renders the message template with the record columns as variables.
+func renderText(record tabular.Record, tmpl *templates.Template) (string, error) {
+	vars := make(map[string]string, len(record.Values))
+	for column, value := range record.Values {
+		vars[column] = strings.TrimSpace(value)
+	}
+
+	text, err := tmpl.Execute(vars)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrValidationFailed, err)
+	}
+
+	return strings.TrimSpace(text), nil
+}
+
+func mapRow(record tabular.Record, mapping map[string]string, opts Options) (SendRow, error) {
 	row := SendRow{
 		RowNumber: record.RowNumber,
+		Recipient: 0,
 		ID:        strings.TrimSpace(record.Values[mapping["id"]]),
-		Phone:     strings.TrimSpace(record.Values[mapping["phone"]]),
+		Phones:    nil,
 		Text:      strings.TrimSpace(record.Values[mapping["text"]]),
 		DeviceID:  strings.TrimSpace(record.Values[mapping["device_id"]]),
+		Data:      strings.TrimSpace(record.Values[mapping["data"]]),
+		DataPort:  0,
 		SimNumber: nil,
 		Priority:  nil,
+
+		TTL:                 nil,
+		ValidUntil:          nil,
+		ScheduleAt:          nil,
+		DeliveryReport:      nil,
+		SkipPhoneValidation: nil,
 	}
 
-	if row.Phone == "" {
-		return SendRow{}, fmt.Errorf("%w: phone is empty", ErrValidationFailed)
+	if opts.Template != nil {
+		text, err := renderText(record, opts.Template)
+		if err != nil {
+			return SendRow{}, err
+		}
+		row.Text = text
 	}
-	if row.Text == "" {
-		return SendRow{}, fmt.Errorf("%w: text is empty", ErrValidationFailed)
+	if err := parseContent(record, mapping, opts, &row); err != nil {
+		return SendRow{}, err
 	}
 
 	if column, ok := mapping["sim_number"]; ok {
@@ -133,5 +160,20 @@ func mapRow(record tabular.Record, mapping map[string]string) (SendRow, error) {
 		row.Priority = priority
 	}
 
+	if err := parseOptions(record, mapping, &row); err != nil {
+		return SendRow{}, err
+	}
+
+	// the recipients are parsed last to honor the per-row skip_phone_validation
+	normalizer := opts.Phones
+	if lo.FromPtrOr(row.SkipPhoneValidation, opts.SkipPhoneValidation) {
+		normalizer = nil
+	}
+	phones, err := parsePhones(record.Values[mapping["phone"]], opts.PhoneSeparator, normalizer)
+	if err != nil {
+		return SendRow{}, err
+	}
+	row.Phones = phones
+
 	return row, nil
 }
@@ -0,0 +1,137 @@
+package mappings
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/android-sms-gateway/cli/pkg/io/tabular"
+	"github.com/samber/lo"
+)
+
+// parseOptions parses the per-row message options mapped to columns.
+func parseOptions(record tabular.Record, mapping map[string]string, row *SendRow) error {
+	var err error
+
+	if row.TTL, err = parseTTL(record, mapping); err != nil {
+		return err
+	}
+	if row.ValidUntil, err = parseTime(record, mapping, "valid_until"); err != nil {
+		return err
+	}
+	if row.ScheduleAt, err = parseTime(record, mapping, "schedule_at"); err != nil {
+		return err
+	}
+	if row.DeliveryReport, err = parseBool(record, mapping, "delivery_report"); err != nil {
+		return err
+	}
+	if row.SkipPhoneValidation, err = parseBool(record, mapping, "skip_phone_validation"); err != nil {
+		return err
+	}
+
+	if row.TTL != nil && row.ValidUntil != nil {
+		return fmt.Errorf("%w: ttl and valid_until are mutually exclusive", ErrValidationFailed)
+	}
+
+	return nil
+}
+
+// lookup returns the trimmed value of the column mapped to key and whether
+// the value is provided. A column missing from a sparse record is empty.
+func lookup(record tabular.Record, mapping map[string]string, key string) (string, bool, error) {
+	column, ok := mapping[key]
+	if !ok {
+		return "", false, nil
+	}
+
+	value, exists := record.Values[column]
+	if !exists && !record.Sparse {
+		return "", false, fmt.Errorf("%w: invalid %s mapping: column %q not found", ErrMappingParseFailed, key, column)
+	}
+	raw := strings.TrimSpace(value)
+
+	return raw, raw != "", nil
+}
+
+// parseTTL accepts a duration (e.g. 1h30m) or a whole number of seconds.
+func parseTTL(record tabular.Record, mapping map[string]string) (*uint64, error) {
+	raw, ok, err := lookup(record, mapping, "ttl")
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	ttl, err := time.ParseDuration(raw)
+	if seconds, parseErr := strconv.ParseUint(raw, 10, 32); parseErr == nil {
+		ttl, err = time.Duration(seconds)*time.Second, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid ttl %q: expected a duration (e.g. 1h30m) or seconds", ErrValidationFailed, raw)
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("%w: ttl must be positive: %s", ErrValidationFailed, raw)
+	}
+	if ttl%time.Second != 0 {
+		return nil, fmt.Errorf("%w: ttl must be a whole number of seconds: %s", ErrValidationFailed, raw)
+	}
+
+	return lo.ToPtr(uint64(ttl / time.Second)), nil
+}
+
+// CheckTimes reports a valid_until or schedule_at of the row that isn't after now.
+// It's separate from the mapping, so rows enqueued by a previous run can be
+// resumed after their times have passed.
+func CheckTimes(row SendRow, now time.Time) error {
+	if row.ValidUntil != nil && !row.ValidUntil.After(now) {
+		return fmt.Errorf(
+			"%w: valid_until must be in the future: %s",
+			ErrValidationFailed,
+			row.ValidUntil.Format(time.RFC3339),
+		)
+	}
+	if row.ScheduleAt != nil && !row.ScheduleAt.After(now) {
+		return fmt.Errorf(
+			"%w: schedule_at must be in the future: %s",
+			ErrValidationFailed,
+			row.ScheduleAt.Format(time.RFC3339),
+		)
+	}
+
+	return nil
+}
+
+// parseTime parses an RFC3339 time.
+func parseTime(record tabular.Record, mapping map[string]string, key string) (*time.Time, error) {
+	raw, ok, err := lookup(record, mapping, key)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid %s %q: expected RFC3339, e.g. 2006-01-02T15:04:05Z07:00", ErrValidationFailed, key, raw)
+	}
+	return lo.ToPtr(t), nil
+}
+
+// parseBool accepts the values of strconv.ParseBool as well as yes and no.
+func parseBool(record tabular.Record, mapping map[string]string, key string) (*bool, error) {
+	raw, ok, err := lookup(record, mapping, key)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	switch strings.ToLower(raw) {
+	case "yes", "y":
+		return lo.ToPtr(true), nil
+	case "no", "n":
+		return lo.ToPtr(false), nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid %s %q: expected true or false", ErrValidationFailed, key, raw)
+	}
+
+	return lo.ToPtr(value), nil
+}
@@ -0,0 +1,95 @@
+package mappings
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/android-sms-gateway/cli/internal/phones"
+)
+
+const defaultPhoneSeparator = ","
+
+// parsePhones splits the phone column into recipients by the separator and
+// line breaks, normalizing them when the normalizer is set. Duplicate numbers
+// are sent once.
+func parsePhones(raw, separator string, normalizer *phones.Normalizer) ([]string, error) {
+	if separator == "" {
+		separator = defaultPhoneSeparator
+	}
+
+	raw = strings.ReplaceAll(raw, "\r\n", "\n")
+	raw = strings.ReplaceAll(raw, separator, "\n")
+	if strings.TrimSpace(raw) == "" {
+		return nil, fmt.Errorf("%w: phone is empty", ErrValidationFailed)
+	}
+
+	parts := strings.Split(raw, "\n")
+	recipients := make([]string, 0, len(parts))
+	seen := make(map[string]struct{}, len(parts))
+	position := 0
+	for _, part := range parts {
+		phone := strings.TrimSpace(part)
+		if phone == "" {
+			// a trailing separator or an empty line is not a recipient
+			continue
+		}
+		position++
+		if !isPhoneNumber(phone) {
+			return nil, fmt.Errorf("%w: invalid phone number #%d: %q", ErrValidationFailed, position, phone)
+		}
+		if normalizer != nil {
+			normalized, err := normalizer.Normalize(phone)
+			if err != nil {
+				return nil, fmt.Errorf("%w: phone #%d: %w", ErrValidationFailed, position, err)
+			}
+			phone = normalized
+		}
+		if _, ok := seen[phone]; ok {
+			continue
+		}
+		seen[phone] = struct{}{}
+		recipients = append(recipients, phone)
+	}
+
+	return recipients, nil
+}
+
+// isPhoneNumber reports whether s consists of digits with an optional
+// leading plus and the usual formatting characters.
+func isPhoneNumber(s string) bool {
+	digits := 0
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '+' && i == 0:
+		case r == ' ', r == '-', r == '(', r == ')', r == '.':
+		default:
+			return false
+		}
+	}
+
+	return digits > 0
+}
+
+// splitRecipients returns a separate row for every recipient of the row.
+// Custom message IDs get the recipient index as a suffix to stay unique.
+func splitRecipients(row SendRow) []SendRow {
+	if len(row.Phones) < 2 { //nolint:mnd // a single recipient is not split
+		return []SendRow{row}
+	}
+
+	rows := make([]SendRow, 0, len(row.Phones))
+	for i, phone := range row.Phones {
+		recipient := row
+		recipient.Recipient = i + 1
+		recipient.Phones = []string{phone}
+		if row.ID != "" {
+			recipient.ID = row.ID + "-" + strconv.Itoa(i+1)
+		}
+		rows = append(rows, recipient)
+	}
+
+	return rows
+}
@@ -1,14 +1,52 @@
 package mappings
 
+import (
+	"time"
+
+	"github.com/android-sms-gateway/cli/internal/phones"
+	"github.com/android-sms-gateway/cli/internal/templates"
+)
+
+// Options control how records are mapped to send rows.
+type Options struct {
+	// Template renders the message text from the record columns instead of the text column.
+	Template *templates.Template
+	// Data makes every row a data message.
+	Data bool
+	// DataPort is the destination port of data messages without a data_port column.
+	DataPort uint16
+	// PhoneSeparator separates the recipients in the phone column, in addition to line breaks.
+	PhoneSeparator string
+	// SplitRecipients maps every recipient of a row to a separate send row.
+	SplitRecipients bool
+	// Phones converts the recipients to E.164, they are kept as is when nil.
+	Phones *phones.Normalizer
+	// SkipPhoneValidation keeps the recipients as is unless a row overrides it.
+	SkipPhoneValidation bool
+}
+
 // SendRow is a normalized row for the batch send flow.
 type SendRow struct {
 	RowNumber int
+	// Recipient is the one-based index of the recipient when the row was split, zero otherwise.
+	Recipient int
 
 	ID       string
-	Phone    string
+	Phones   []string
 	Text     string
 	DeviceID string
 
+	// Data is the base64 payload of a data message, the row is a text message when empty.
+	Data     string
+	DataPort uint16
+
 	SimNumber *uint8
 	Priority  *int8
+
+	// Per-row message options; nil values fall back to the command flags.
+	TTL                 *uint64
+	ValidUntil          *time.Time
+	ScheduleAt          *time.Time
+	DeliveryReport      *bool
+	SkipPhoneValidation *bool
 }
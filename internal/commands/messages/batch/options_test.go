@@ -0,0 +1,82 @@
+package batch_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchSend_PerRowOptions(t *testing.T) {
+	t.Parallel()
+
+	scheduleAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	validUntil := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.write(t, "input.csv", fmt.Sprintf(
+		"Phone,Message,TTL,ValidUntil,ScheduleAt,Report,Skip\n"+
+			"+12025550123,Hello,1h,,%s,no,yes\n"+
+			"+12025550124,Hello,,%s,,,\n",
+		scheduleAt.Format(time.RFC3339),
+		validUntil.Format(time.RFC3339),
+	))
+
+	require.NoError(t, f.run(t,
+		"--map", "phone=Phone,text=Message,ttl=TTL,valid_until=ValidUntil,schedule_at=ScheduleAt,"+
+			"delivery_report=Report,skip_phone_validation=Skip",
+		"--ttl", "10m",
+		path,
+	))
+
+	requests, queries := f.server.captured()
+
+	first := requests["+12025550123"]
+	require.NotNil(t, first)
+	assert.InDelta(t, 3600, first["ttl"], 0)
+	assert.Equal(t, scheduleAt.Format(time.RFC3339), first["scheduleAt"])
+	assert.Equal(t, false, first["withDeliveryReport"])
+	assert.Equal(t, "true", queries["+12025550123"])
+
+	// the row expiration replaces --ttl, unset columns fall back to the flags
+	second := requests["+12025550124"]
+	require.NotNil(t, second)
+	assert.NotContains(t, second, "ttl")
+	assert.Equal(t, validUntil.Format(time.RFC3339), second["validUntil"])
+	assert.NotContains(t, second, "scheduleAt")
+	assert.Equal(t, true, second["withDeliveryReport"])
+	assert.Empty(t, queries["+12025550124"])
+}
+
+func TestBatchSend_InvalidPerRowOptions(t *testing.T) {
+	t.Parallel()
+
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.write(t, "input.csv",
+		"Phone,Message,TTL,ValidUntil,ScheduleAt,Report\n"+
+			"+12025550123,Hello,soon,,,\n"+
+			"+12025550124,Hello,,"+past+",,\n"+
+			"+12025550125,Hello,,,tomorrow,\n"+
+			"+12025550126,Hello,,,,maybe\n"+
+			"+12025550127,Hello,60,"+future+",,\n"+
+			"+12025550128,Hello,90s,,"+future+",true\n",
+	)
+
+	err := f.run(t,
+		"--map", "phone=Phone,text=Message,ttl=TTL,valid_until=ValidUntil,schedule_at=ScheduleAt,delivery_report=Report",
+		"--validate-only",
+		path,
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `row 2: validation failed: invalid ttl "soon"`)
+	assert.Contains(t, err.Error(), "row 3: validation failed: valid_until must be in the future")
+	assert.Contains(t, err.Error(), `row 4: validation failed: invalid schedule_at "tomorrow"`)
+	assert.Contains(t, err.Error(), `row 5: validation failed: invalid delivery_report "maybe"`)
+	assert.Contains(t, err.Error(), "row 6: validation failed: ttl and valid_until are mutually exclusive")
+	assert.NotContains(t, err.Error(), "row 7")
+}
@@ -0,0 +1,93 @@
+package batch_test
+
+import (
+	"testing"
+
+	"github.com/android-sms-gateway/cli/internal/config"
+	"github.com/android-sms-gateway/cli/internal/utils/metadata"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchSend_DefaultRegion(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.write(t, "input.csv",
+		"Phone,Message,Skip\n"+
+			"\"(202) 555-0123, 1 202 555 0123\",Hello,\n"+
+			"+1 202 555 0124,Hello,\n"+
+			"+1 555-0125,Hello,yes\n",
+	)
+
+	require.NoError(t, f.run(t,
+		"--map", "phone=Phone,text=Message,skip_phone_validation=Skip",
+		"--default-region", "us",
+		path,
+	))
+
+	assert.ElementsMatch(t, []string{"+12025550123", "+12025550124", "+1 555-0125"}, f.server.sent())
+}
+
+func TestBatchSend_ProfileSkipsPhoneValidation(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.write(t, "input.csv", "Phone,Message\n+1 555-0123,Hello\n")
+
+	ctx := f.context(t,
+		"--map", "phone=Phone,text=Message",
+		"--default-region", "US",
+		path,
+	)
+	ctx.App.Metadata[metadata.ProfileKey] = config.Profile{
+		Send: config.SendDefaults{SkipPhoneValidation: lo.ToPtr(true)},
+	}
+	require.NoError(t, f.cmd.Before(ctx))
+	require.NoError(t, f.cmd.Action(ctx))
+
+	assert.Equal(t, []string{"+1 555-0123"}, f.server.sent())
+}
+
+func TestBatchSend_InvalidPhonesForRegion(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.write(t, "input.csv",
+		"Phone,Message\n"+
+			"(202) 555-0123,Hello\n"+
+			"555-0123,Hello\n"+
+			"\"+12025550124, +1 202 555 012\",Hello\n",
+	)
+
+	err := f.run(t,
+		"--map", "phone=Phone,text=Message",
+		"--default-region", "US",
+		"--validate-only",
+		path,
+	)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "row 2:")
+	assert.Contains(t, err.Error(), `row 3: validation failed: phone #1: invalid phone number "555-0123": `+
+		"not a possible number for region US")
+	assert.Contains(t, err.Error(), `row 4: validation failed: phone #2: invalid phone number "+1 202 555 012": `+
+		"not a possible number for country code +1")
+}
+
+func TestBatchSend_UnknownRegion(t *testing.T) {
+	t.Parallel()
+
+	path, cmd, ok := newBatchSendFixture(t)
+	require.True(t, ok)
+
+	ctx := newContext(t, cmd, []string{
+		"--map", "phone=Phone,text=Message",
+		"--default-region", "XX",
+		path,
+	})
+
+	err := cmd.Before(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown region "XX"`)
+}
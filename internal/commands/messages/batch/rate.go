@@ -0,0 +1,42 @@
+package batch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// parseRate parses a rate in the form N/s, N/m or N/h. An empty value means no limit.
+func parseRate(value string) (rate.Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return rate.Inf, nil
+	}
+
+	count, unit, ok := strings.Cut(value, "/")
+	if !ok {
+		return 0, fmt.Errorf("%w: rate %q, expected N/s, N/m or N/h", ErrValidationFailed, value)
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(count), 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%w: rate %q, count must be a positive number", ErrValidationFailed, value)
+	}
+
+	var per time.Duration
+	switch strings.TrimSpace(unit) {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return 0, fmt.Errorf("%w: rate %q, unit must be s, m or h", ErrValidationFailed, value)
+	}
+
+	return rate.Every(time.Duration(float64(per) / n)), nil
+}
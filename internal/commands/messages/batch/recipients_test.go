@@ -0,0 +1,88 @@
+package batch_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchSend_MultipleRecipients(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.write(t, "input.csv",
+		"Phone,Message\n"+
+			"\"+12025550123; +12025550124\n+12025550125;\",Hello group\n"+
+			"+12025550126,Hello\n",
+	)
+
+	require.NoError(t, f.run(t,
+		"--map", "phone=Phone,text=Message",
+		"--phone-separator", ";",
+		path,
+	))
+
+	requests, _ := f.server.captured()
+	require.Len(t, requests, 2)
+	assert.Equal(t,
+		[]any{"+12025550123", "+12025550124", "+12025550125"},
+		requests["+12025550123"]["phoneNumbers"],
+	)
+	assert.Equal(t, []any{"+12025550126"}, requests["+12025550126"]["phoneNumbers"])
+}
+
+func TestBatchSend_SplitRecipients(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{fail: map[string]bool{"+12025550124": true}})
+	path := f.write(t, "input.csv",
+		"ID,Phone,Message\n"+
+			"grp,\"+12025550123, +12025550124, +12025550123\",Hello group\n"+
+			"single,+12025550125,Hello\n",
+	)
+	reportPath := f.path("report.csv")
+
+	require.Error(t, f.run(t,
+		"--map", "id=ID,phone=Phone,text=Message",
+		"--split-recipients",
+		"--continue-on-error",
+		"--report", reportPath,
+		path,
+	))
+
+	// duplicates in a cell are sent once
+	assert.ElementsMatch(t, []string{"+12025550123", "+12025550124", "+12025550125"}, f.server.sent())
+
+	rows := readReport(t, reportPath)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"2", "grp-1", "+12025550123", "enqueued"},
+		[]string{rows[0]["row"], rows[0]["id"], rows[0]["phone"], rows[0]["status"]})
+	assert.Equal(t, []string{"2", "grp-2", "+12025550124", "failed"},
+		[]string{rows[1]["row"], rows[1]["id"], rows[1]["phone"], rows[1]["status"]})
+	assert.Equal(t, []string{"3", "single", "+12025550125", "enqueued"},
+		[]string{rows[2]["row"], rows[2]["id"], rows[2]["phone"], rows[2]["status"]})
+}
+
+func TestBatchSend_InvalidRecipients(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.write(t, "input.csv",
+		"Phone,Message\n"+
+			"\"+12025550123, call me\",Hello\n"+
+			"\" , \",Hello\n"+
+			"\"+1 (202) 555-0124,\",Hello\n",
+	)
+
+	err := f.run(t,
+		"--map", "phone=Phone,text=Message",
+		"--split-recipients",
+		"--validate-only",
+		path,
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `row 2: validation failed: invalid phone number #2: "call me"`)
+	assert.Contains(t, err.Error(), "row 3: validation failed: phone is empty")
+	assert.NotContains(t, err.Error(), "row 4")
+}
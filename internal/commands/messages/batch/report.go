@@ -0,0 +1,239 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/android-sms-gateway/cli/pkg/io/tabular"
+)
+
+const reportFileMode = 0o600
+
+// reportRow is the outcome of a single input row.
+type reportRow struct {
+	Row      int    `json:"row"`
+	ID       string `json:"id"`
+	Phone    string `json:"phone"`
+	Text     string `json:"text"`
+	Status   string `json:"status"`
+	Error    string `json:"error"`
+	Attempts int    `json:"attempts"`
+	DeviceID string `json:"device_id"`
+	// SimNumber is nil for the default SIM.
+	SimNumber *uint8 `json:"sim_number"`
+
+	// The message options of the row, so the report can be fed back as input.
+	// Nil values are unset and fall back to the command flags.
+	Data           string     `json:"data"`
+	DataPort       *uint16    `json:"data_port"`
+	TTL            *uint64    `json:"ttl"`
+	ValidUntil     *time.Time `json:"valid_until"`
+	ScheduleAt     *time.Time `json:"schedule_at"`
+	DeliveryReport *bool      `json:"delivery_report"`
+	Priority       *int8      `json:"priority"`
+}
+
+// validateReportPath checks that the report format is supported.
+func validateReportPath(path string) error {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv", ".json", ".xlsx":
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported report extension %q; use .csv, .json or .xlsx", ErrValidationFailed, ext)
+	}
+}
+
+// newReportRow returns the report line of the row outcome.
+func newReportRow(result batchRowResult) reportRow {
+	line := reportRow{
+		Row:      result.Row.RowNumber,
+		ID:       result.Identifier,
+		Phone:    strings.Join(result.Row.Phones, ","),
+		Text:     result.Row.Text,
+		Status:   result.Status,
+		Error:    "",
+		Attempts: result.Attempts,
+
+		DeviceID:  result.Target.DeviceID,
+		SimNumber: result.Target.SimNumber,
+
+		Data:           result.Row.Data,
+		DataPort:       nil,
+		TTL:            result.Row.TTL,
+		ValidUntil:     result.Row.ValidUntil,
+		ScheduleAt:     result.Row.ScheduleAt,
+		DeliveryReport: result.Row.DeliveryReport,
+		Priority:       result.Row.Priority,
+	}
+	if result.Row.Data != "" {
+		line.DataPort = &result.Row.DataPort
+	}
+	if line.ID == "" {
+		line.ID = result.Row.ID
+	}
+	if result.Error != nil {
+		line.Error = result.Error.Error()
+	}
+
+	return line
+}
+
+// reportHeader returns the columns of the CSV and XLSX reports, named after the
+// --map keys of the values.
+func reportHeader() []string {
+	return []string{
+		"row", "id", "phone", "text", "status", "error", "attempts", "device_id", "sim_number",
+		"data", "data_port", "ttl", "valid_until", "schedule_at", "delivery_report",
+		"priority",
+	}
+}
+
+// Values returns the CSV and XLSX cells in the order of reportHeader, unset
+// values are empty.
+func (r reportRow) Values() []string {
+	return []string{
+		strconv.Itoa(r.Row),
+		r.ID,
+		r.Phone,
+		r.Text,
+		r.Status,
+		r.Error,
+		strconv.Itoa(r.Attempts),
+		r.DeviceID,
+		formatOptional(r.SimNumber, func(v uint8) string { return strconv.Itoa(int(v)) }),
+		r.Data,
+		formatOptional(r.DataPort, func(v uint16) string { return strconv.Itoa(int(v)) }),
+		formatOptional(r.TTL, func(v uint64) string { return strconv.FormatUint(v, 10) }),
+		formatOptional(r.ValidUntil, func(v time.Time) string { return v.Format(time.RFC3339) }),
+		formatOptional(r.ScheduleAt, func(v time.Time) string { return v.Format(time.RFC3339) }),
+		formatOptional(r.DeliveryReport, strconv.FormatBool),
+		formatOptional(r.Priority, func(v int8) string { return strconv.Itoa(int(v)) }),
+	}
+}
+
+func formatOptional[T any](v *T, format func(T) string) string {
+	if v == nil {
+		return ""
+	}
+
+	return format(*v)
+}
+
+// reportWriter writes the report in the background as the rows are processed,
+// so the report doesn't have to fit in memory. A nil writer writes nothing.
+type reportWriter struct {
+	rows chan reportRow
+	done chan error
+}
+
+func startReport(ctx context.Context, path string) *reportWriter {
+	if path == "" {
+		return nil
+	}
+
+	w := &reportWriter{rows: make(chan reportRow), done: make(chan error, 1)}
+	go func() {
+		err := writeReport(ctx, path, func(yield func(reportRow) bool) {
+			for row := range w.rows {
+				if !yield(row) {
+					return
+				}
+			}
+		})
+		// keep accepting rows after a failure, the error is returned by Close
+		for range w.rows {
+		}
+		w.done <- err
+	}()
+
+	return w
+}
+
+func (w *reportWriter) Add(result batchRowResult) {
+	if w == nil {
+		return
+	}
+
+	w.rows <- newReportRow(result)
+}
+
+// Close finishes the report and returns the write error, if any.
+func (w *reportWriter) Close() error {
+	if w == nil {
+		return nil
+	}
+
+	close(w.rows)
+	return <-w.done
+}
+
+// writeReport writes the report in the format of the file extension.
+func writeReport(ctx context.Context, path string, report iter.Seq[reportRow]) error {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".json" {
+		return writeJSONReport(path, report)
+	}
+
+	lines := func(yield func([]string) bool) {
+		for r := range report {
+			if !yield(r.Values()) {
+				return
+			}
+		}
+	}
+
+	var writer tabular.Writer
+	if ext == ".xlsx" {
+		writer = tabular.NewXLSXWriter(tabular.XLSXConfig{Path: path, Input: nil, Sheet: "", HasHeader: true})
+	} else {
+		writer = tabular.NewCSVWriter(tabular.CSVConfig{Path: path, Input: nil, Delimiter: 0, HasHeader: true})
+	}
+
+	if err := writer.Write(ctx, reportHeader(), lines); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+
+	return nil
+}
+
+// writeJSONReport writes the report as a JSON array, one object per row.
+func writeJSONReport(path string, report iter.Seq[reportRow]) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, reportFileMode)
+	if err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	defer f.Close()
+
+	separator := "[\n  "
+	for r := range report {
+		b, marshalErr := json.MarshalIndent(r, "  ", "  ")
+		if marshalErr != nil {
+			return fmt.Errorf("marshal report: %w", marshalErr)
+		}
+		if _, writeErr := fmt.Fprint(f, separator, string(b)); writeErr != nil {
+			return fmt.Errorf("write report: %w", writeErr)
+		}
+		separator = ",\n  "
+	}
+
+	end := "\n]\n"
+	if separator == "[\n  " {
+		end = "[]\n"
+	}
+	if _, writeErr := fmt.Fprint(f, end); writeErr != nil {
+		return fmt.Errorf("write report: %w", writeErr)
+	}
+
+	if closeErr := f.Close(); closeErr != nil {
+		return fmt.Errorf("write report: %w", closeErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package batch_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/cli/pkg/io/tabular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchSend_Report(t *testing.T) {
+	t.Parallel()
+
+	for _, ext := range []string{".csv", ".json", ".xlsx"} {
+		t.Run(ext, func(t *testing.T) {
+			t.Parallel()
+
+			f := newSendFixture(t, &sendServer{fail: map[string]bool{"+12025550124": true}})
+			path := f.write(t, "input.csv",
+				"ID,Phone,Message\nmsg-1,+12025550123,Hello\nmsg-2,+12025550124,Hello\nmsg-3,+12025550125,Hello\n",
+			)
+			reportPath := f.path("report" + ext)
+
+			require.Error(t, f.run(t,
+				"--map", "id=ID,phone=Phone,text=Message",
+				"--concurrency", "1",
+				"--report", reportPath,
+				path,
+			))
+
+			rows := readReport(t, reportPath)
+			require.Len(t, rows, 3)
+
+			assert.Equal(t, map[string]string{
+				"row": "2", "id": "msg-1", "phone": "+12025550123", "text": "Hello",
+				"status": "enqueued", "error": "", "attempts": "1", "device_id": "", "sim_number": "",
+				"data": "", "data_port": "", "ttl": "", "valid_until": "", "schedule_at": "", "delivery_report": "",
+				"priority": "",
+			}, rows[0])
+			assert.Equal(t, "failed", rows[1]["status"])
+			assert.Contains(t, rows[1]["error"], "400")
+			assert.Equal(t, "1", rows[1]["attempts"])
+			assert.Equal(t, map[string]string{
+				"row": "4", "id": "msg-3", "phone": "+12025550125", "text": "Hello",
+				"status": "skipped", "error": "", "attempts": "0", "device_id": "", "sim_number": "",
+				"data": "", "data_port": "", "ttl": "", "valid_until": "", "schedule_at": "", "delivery_report": "",
+				"priority": "",
+			}, rows[2])
+
+			info, err := os.Stat(reportPath)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		})
+	}
+}
+
+func TestBatchSend_ReportResumed(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{fail: map[string]bool{"+12025550124": true}})
+	path := f.writePhones(t, "+12025550123", "+12025550124")
+	reportPath := f.path("report.csv")
+
+	args := []string{
+		"--map", "phone=Phone,text=Message",
+		"--continue-on-error",
+		"--journal", f.path("journal.jsonl"),
+		"--resume",
+		"--report", reportPath,
+		path,
+	}
+
+	require.Error(t, f.run(t, args...))
+	first := readReport(t, reportPath)
+	require.Len(t, first, 2)
+
+	f.server.reset()
+	require.NoError(t, f.run(t, args...))
+
+	rows := readReport(t, reportPath)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "resumed", rows[0]["status"])
+	assert.Equal(t, first[0]["id"], rows[0]["id"])
+	assert.Equal(t, "enqueued", rows[1]["status"])
+}
+
+func TestBatchSend_ReportFedBack(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{})
+	scheduleAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second).Format(time.RFC3339)
+	path := f.write(t, "input.csv", "Phone,Message,Data,Port,TTL,Schedule,Report,Priority\n"+
+		"+12025550123,Hello,,,3600,,false,100\n"+
+		"+12025550124,,SGVsbG8=,53739,,"+scheduleAt+",,\n")
+	reportPath := f.path("report.csv")
+
+	require.NoError(t, f.run(t,
+		"--map", "phone=Phone,text=Message,data=Data,data_port=Port,ttl=TTL,schedule_at=Schedule,"+
+			"delivery_report=Report,priority=Priority",
+		"--concurrency", "1",
+		"--report", reportPath,
+		path,
+	))
+
+	rows := readReport(t, reportPath)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "3600", rows[0]["ttl"])
+	assert.Equal(t, "false", rows[0]["delivery_report"])
+	assert.Equal(t, "100", rows[0]["priority"])
+	assert.Empty(t, rows[0]["data_port"])
+	assert.Equal(t, "SGVsbG8=", rows[1]["data"])
+	assert.Equal(t, "53739", rows[1]["data_port"])
+	assert.Equal(t, scheduleAt, rows[1]["schedule_at"])
+	assert.Empty(t, rows[1]["delivery_report"])
+	assert.Empty(t, rows[1]["priority"])
+
+	first, _ := f.server.captured()
+	f.server.reset()
+
+	// the report is valid input sending the same messages
+	require.NoError(t, f.run(t,
+		"--map", "id=id,phone=phone,text=text,data=data,data_port=data_port,ttl=ttl,"+
+			"valid_until=valid_until,schedule_at=schedule_at,delivery_report=delivery_report,priority=priority",
+		"--concurrency", "1",
+		reportPath,
+	))
+
+	resent, _ := f.server.captured()
+	assert.Equal(t, first, resent)
+	assert.EqualValues(t, 100, resent["+12025550123"]["priority"])
+}
+
+func TestBatchSend_ReportInvalidExtension(t *testing.T) {
+	t.Parallel()
+
+	path, cmd, ok := newBatchSendFixture(t)
+	require.True(t, ok)
+
+	ctx := newContext(t, cmd, []string{
+		"--map", "phone=Phone,text=Message",
+		"--report", "report.txt",
+		path,
+	})
+
+	err := cmd.Before(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported report extension")
+}
+
+// readReport reads the report rows as maps of column to value.
+func readReport(t *testing.T, path string) []map[string]string {
+	t.Helper()
+
+	var reader tabular.Reader
+	switch filepath.Ext(path) {
+	case ".json":
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		var items []map[string]any
+		require.NoError(t, json.Unmarshal(b, &items))
+
+		rows := make([]map[string]string, 0, len(items))
+		for _, item := range items {
+			row := map[string]string{}
+			for k, v := range item {
+				row[k] = ""
+				if v != nil {
+					row[k] = fmt.Sprint(v)
+				}
+			}
+			rows = append(rows, row)
+		}
+		return rows
+	case ".xlsx":
+		reader = tabular.NewXLSXReader(tabular.XLSXConfig{Path: path, HasHeader: true})
+	default:
+		reader = tabular.NewCSVReader(tabular.CSVConfig{Path: path, HasHeader: true})
+	}
+
+	records, err := reader.Read(context.Background())
+	require.NoError(t, err)
+
+	rows := make([]map[string]string, 0, len(records))
+	for _, record := range records {
+		row := map[string]string{}
+		for _, column := range []string{
+			"row", "id", "phone", "text", "status", "error", "attempts", "device_id", "sim_number",
+			"data", "data_port", "ttl", "valid_until", "schedule_at", "delivery_report", "priority",
+		} {
+			row[column] = record.Values[column]
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
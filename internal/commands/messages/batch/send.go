@@ -2,20 +2,28 @@ package batch
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"iter"
+	"maps"
 	"os"
-	"path/filepath"
+	"os/signal"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
+	"syscall"
 
 	"github.com/android-sms-gateway/cli/internal/commands/flags"
 	"github.com/android-sms-gateway/cli/internal/commands/messages/batch/mappings"
 	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/core/output"
+	"github.com/android-sms-gateway/cli/internal/core/ratelimit"
+	"github.com/android-sms-gateway/cli/internal/core/retry"
 	"github.com/android-sms-gateway/cli/internal/utils/metadata"
-	"github.com/android-sms-gateway/cli/pkg/io/tabular"
 	"github.com/android-sms-gateway/client-go/smsgateway"
 	"github.com/google/uuid"
+	"github.com/samber/lo"
 	"github.com/urfave/cli/v2"
 )
 
@@ -34,6 +42,12 @@ func batchSendCmd() *cli.Command {
 			Value:    ",",
 		},
 
+		&cli.StringFlag{
+			Name:     "input-format",
+			Category: "Input",
+			Usage:    "Input format: csv, xlsx, json or ndjson (defaults to the file extension, required for stdin)",
+			Value:    "",
+		},
 		&cli.BoolFlag{
 			Name:     "header",
 			Category: "Input",
@@ -43,9 +57,36 @@ func batchSendCmd() *cli.Command {
 		&cli.StringFlag{
 			Name:     "map",
 			Category: "Input",
-			Usage:    "Column mapping, e.g. phone=Phone,text=Message,id=ID,device_id=Device,sim_number=SIM,priority=Priority",
+			Usage: "Column mapping, e.g. phone=Phone,text=Message,id=ID,device_id=Device,sim_number=SIM,priority=Priority; " +
+				"per-row ttl, valid_until, schedule_at, delivery_report and skip_phone_validation are also supported; " +
+				"text may be omitted when a message template is used",
 			Required: true,
 		},
+		&cli.StringFlag{
+			Name:     "phone-separator",
+			Category: "Input",
+			Usage:    "Separator of several recipients in the phone column, line breaks always separate them",
+			Value:    ",",
+		},
+		&cli.BoolFlag{
+			Name:     "split-recipients",
+			Category: "Input",
+			Usage:    "Send a separate message to every recipient of a row instead of one multi-recipient message",
+			Value:    false,
+		},
+
+		&cli.StringFlag{
+			Name:     "dedupe",
+			Category: "Suppression",
+			Usage:    "Drop recipients already messaged earlier in the batch: phone or phone+text",
+			Value:    "",
+		},
+		&cli.StringFlag{
+			Name:     "suppress-list",
+			Category: "Suppression",
+			Usage:    "CSV file of phone numbers that must not be messaged, in addition to the opt-out list",
+			Value:    "",
+		},
 
 		&cli.BoolFlag{
 			Name:     "dry-run",
@@ -60,6 +101,12 @@ func batchSendCmd() *cli.Command {
 			Value:    false,
 		},
 
+		&cli.BoolFlag{
+			Name:     "stream",
+			Category: "Send",
+			Usage:    "Read, validate and send the rows one at a time instead of validating the whole input first",
+			Value:    false,
+		},
 		&cli.IntFlag{
 			Name:     "concurrency",
 			Category: "Send",
@@ -72,13 +119,83 @@ func batchSendCmd() *cli.Command {
 			Usage:    "Continue sending after per-row failures",
 			Value:    false,
 		},
+		&cli.StringFlag{
+			Name:     "rate",
+			Category: "Send",
+			Usage:    "Maximum send rate shared by all workers, e.g. 10/s, 300/m or 1000/h",
+			Value:    "",
+		},
+		&cli.IntFlag{
+			Name:     "burst",
+			Category: "Send",
+			Usage:    "Number of messages that may be sent at once above --rate",
+			Value:    1,
+		},
+
+		&cli.StringFlag{
+			Name:     "devices",
+			Category: "Devices",
+			Usage: "Device IDs to spread the rows across, comma-separated; " +
+				"append :N to set the weight for the weighted strategy, e.g. id1:3,id2",
+			Value: "",
+		},
+		&cli.StringFlag{
+			Name:     "sims",
+			Category: "Devices",
+			Usage:    "SIM numbers to spread the rows across, comma-separated, e.g. 1,2",
+			Value:    "",
+		},
+		&cli.StringFlag{
+			Name:     "strategy",
+			Category: "Devices",
+			Usage:    "How rows are assigned to the device and SIM pairs: round-robin, weighted or sticky-by-phone",
+			Value:    strategyRoundRobin,
+		},
+		&cli.IntFlag{
+			Name:        "device-concurrency",
+			Category:    "Devices",
+			Usage:       "Maximum number of concurrent sends per device",
+			Value:       0,
+			DefaultText: "unlimited",
+		},
+		&cli.StringFlag{
+			Name:     "device-rate",
+			Category: "Devices",
+			Usage:    "Maximum send rate per device, e.g. 1/s or 30/m",
+			Value:    "",
+		},
+
+		&cli.StringFlag{
+			Name:     "journal",
+			Category: "Resume",
+			Usage:    "Record the outcome of every row in the file to resume an interrupted batch",
+			Value:    "",
+		},
+		&cli.BoolFlag{
+			Name:     "resume",
+			Category: "Resume",
+			Usage:    "Skip rows already enqueued according to the journal",
+			Value:    false,
+		},
+
+		&cli.StringFlag{
+			Name:     "report",
+			Category: "Output",
+			Usage:    "Write the outcome of every row to the file: .csv, .json or .xlsx",
+			Value:    "",
+		},
 	}
+	fl = append(fl, flags.Phone()...)
+	fl = append(fl, flags.Data()...)
 	fl = append(fl, flags.Send()...)
+	fl = append(fl, flags.Template()...)
+	fl = append(fl, flags.Encryption()...)
+	fl = append(fl, flags.Retry()...)
 
 	return &cli.Command{
 		Name:      "send",
-		Usage:     "Validate, preview, and send bulk messages from CSV/XLSX",
-		ArgsUsage: "filename.[csv|xlsx]",
+		Usage:     "Validate, preview, and send bulk messages from CSV/XLSX/JSON/NDJSON",
+		ArgsUsage: "filename.[csv|xlsx|json|ndjson] or - for stdin",
 		Args:      true,
 		Flags:     fl,
 		Before:    batchSendBefore,
@@ -91,15 +208,67 @@ func batchSendBefore(c *cli.Context) error {
 		return cli.Exit("filename is required", codes.ParamsError)
 	}
 
+	if _, err := inputFormat(c); err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+
 	delimiter := c.String("delimiter")
 	if len([]rune(delimiter)) != 1 {
 		return cli.Exit("delimiter must be exactly one character", codes.ParamsError)
 	}
 
+	if c.String("phone-separator") == "" {
+		return cli.Exit("phone separator must not be empty", codes.ParamsError)
+	}
+	if _, err := flags.NewPhoneNormalizer(c); err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+	if dedupe := c.String("dedupe"); dedupe != "" && dedupe != dedupePhone && dedupe != dedupePhoneText {
+		return cli.Exit("dedupe must be one of: phone, phone+text", codes.ParamsError)
+	}
+
 	if c.Int("concurrency") < 1 {
 		return cli.Exit("concurrency must be at least 1", codes.ParamsError)
 	}
 
+	if _, err := parseRate(c.String("rate")); err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+	if c.Int("burst") < 1 {
+		return cli.Exit("burst must be at least 1", codes.ParamsError)
+	}
+
+	if _, err := flags.NewRetryPolicy(c); err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+
+	if _, err := newDistributor(c); err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+	if _, err := newDeviceLimits(c); err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+
+	if path := c.String("report"); path != "" {
+		if err := validateReportPath(path); err != nil {
+			return cli.Exit(err.Error(), codes.ParamsError)
+		}
+	}
+
+	if c.Bool("resume") && c.String("journal") == "" {
+		return cli.Exit("resume requires --journal", codes.ParamsError)
+	}
+
+	if _, ok := flags.DataPort(c); !ok {
+		return cli.Exit("data port must be between 1 and 65535", codes.ParamsError)
+	}
+
+	return validateMapping(c)
+}
+
+// validateMapping checks that the mapping provides the phone and exactly one
+// source of the message content.
+func validateMapping(c *cli.Context) error {
 	mapping, err := mappings.ParseColumnMapping(c.String("map"))
 	if err != nil {
 		return cli.Exit(err.Error(), codes.ParamsError)
@@ -108,231 +277,517 @@ func batchSendBefore(c *cli.Context) error {
 	if _, ok := mapping["phone"]; !ok {
 		return cli.Exit("map must include phone=<column>", codes.ParamsError)
 	}
-	if _, ok := mapping["text"]; !ok {
-		return cli.Exit("map must include text=<column>", codes.ParamsError)
+
+	_, hasText := mapping["text"]
+	_, hasData := mapping["data"]
+	if _, hasPort := mapping["data_port"]; hasPort && !hasData {
+		return cli.Exit("map data_port=<column> requires data=<column>", codes.ParamsError)
+	}
+
+	switch {
+	case c.Bool("data") && !hasData:
+		return cli.Exit("data messages require map data=<column>", codes.ParamsError)
+	case c.Bool("data") && (hasText || flags.HasTemplate(c)):
+		return cli.Exit("data messages can't be combined with text=<column> or a message template", codes.ParamsError)
+	case flags.HasTemplate(c) && hasText:
+		return cli.Exit("map text=<column> can't be combined with a message template", codes.ParamsError)
+	case flags.HasTemplate(c) && hasData:
+		return cli.Exit("map data=<column> can't be combined with a message template", codes.ParamsError)
+	case !flags.HasTemplate(c) && !hasText && !hasData:
+		return cli.Exit(
+			"map must include text=<column> or data=<column>, or a message template must be set",
+			codes.ParamsError,
+		)
 	}
 
 	return nil
 }
 
 func batchSendAction(c *cli.Context) error {
-	renderer := metadata.GetRenderer(c.App.Metadata)
 	sendFlags, err := flags.NewSendFlags(c)
 	if err != nil {
 		return cli.Exit(err.Error(), codes.ParamsError)
 	}
 
-	reader, err := newTabularReader(c)
+	if c.Bool("validate-only") {
+		return validateOnly(c)
+	}
+
+	if c.Bool("dry-run") {
+		rows, readErr := readRows(c, nil)
+		if readErr != nil {
+			return readErr
+		}
+		printPreview(rows)
+		return nil
+	}
+
+	// the journal is read first, as the rows it marks as enqueued skip the time checks
+	journal, err := openJournal(c.String("journal"), c.Bool("resume"))
 	if err != nil {
 		return cli.Exit(err.Error(), codes.ParamsError)
 	}
+	defer journal.Close()
 
-	records, err := reader.Read(c.Context)
+	rows, err := sourceRows(c, journal)
 	if err != nil {
+		return err
+	}
+
+	if resumed := journal.EnqueuedCount(); resumed > 0 {
+		fmt.Fprintf(os.Stderr, "Resuming: %d rows already enqueued\n", resumed)
+	}
+
+	limit, _ := parseRate(c.String("rate"))
+	policy, _ := flags.NewRetryPolicy(c)
+	policy.RateLimitRetries = rateLimitRetries
+	devices, _ := newDeviceLimits(c)
+
+	renderer := metadata.GetRenderer(c.App.Metadata)
+	report := startReport(c.Context, c.String("report"))
+
+	// on interrupt the sending stops and the outcomes of the messages in flight
+	// are still journaled, a second interrupt terminates immediately
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	context.AfterFunc(ctx, stop)
+
+	var printErr error
+	stats, err := runBatchSend(
+		ctx,
+		metadata.GetClient(c.App.Metadata),
+		rows,
+		batchConfig{
+			SendFlags:       sendFlags,
+			Encryptor:       flags.NewEncryptor(c),
+			Concurrency:     c.Int("concurrency"),
+			ContinueOnError: c.Bool("continue-on-error"),
+			Limiter:         ratelimit.New(limit, c.Int("burst")),
+			Retry:           policy,
+			Devices:         devices,
+		},
+		journal,
+		func(result batchRowResult) {
+			report.Add(result)
+			if result.Status == statusEnqueued && printErr == nil {
+				printErr = printState(renderer, result.State)
+			}
+		},
+	)
+
+	printSummary(stats)
+	if reportErr := report.Close(); reportErr != nil {
+		return cli.Exit(reportErr.Error(), codes.OutputError)
+	}
+	if errors.Is(err, ErrReadInput) {
 		return cli.Exit(err.Error(), codes.ParamsError)
 	}
+	if err != nil {
+		return cli.Exit(err.Error(), codes.InternalError)
+	}
 
-	mapping, _ := mappings.ParseColumnMapping(c.String("map"))
-	rows, errs := mappings.MapAndValidateRows(records, mapping)
-	if len(errs) > 0 {
-		return cli.Exit(strings.Join(errs, "\n"), codes.ParamsError)
+	if printErr != nil {
+		return cli.Exit(printErr.Error(), codes.OutputError)
 	}
 
-	if c.Bool("validate-only") {
-		fmt.Fprintf(os.Stderr, "Validation passed: %d rows\n", len(rows))
-		return nil
+	if stats.Failed > 0 {
+		return cli.Exit("one or more rows failed", codes.ClientError)
 	}
 
-	if c.Bool("dry-run") {
-		fmt.Fprintf(os.Stderr, "Dry run successful: %d rows\n", len(rows))
-		for _, row := range rows {
-			fmt.Fprintf(
-				os.Stderr,
-				"row=%d id=%q phone=%q text=%q device_id=%q\n",
-				row.RowNumber,
-				row.ID,
-				row.Phone,
-				row.Text,
-				row.DeviceID,
-			)
+	return nil
+}
+
+// validateOnly validates the whole input without sending anything.
+func validateOnly(c *cli.Context) error {
+	count, dropped := 0, 0
+	err := scanRows(c, nil, func(item rowItem) {
+		if item.Dropped != nil {
+			dropped++
+			return
 		}
-		return nil
+		count++
+	})
+	if err != nil {
+		return err
 	}
+	fmt.Fprintf(os.Stderr, "Validation passed: %d rows%s\n", count, droppedSuffix(dropped))
 
-	client := metadata.GetClient(c.App.Metadata)
-	results := runBatchSend(
-		c.Context,
-		client,
-		rows,
-		sendFlags,
-		c.Int("concurrency"),
-		c.Bool("continue-on-error"),
-	)
+	return nil
+}
 
-	failed := 0
-	for _, result := range results {
-		if result.Error != nil {
-			failed++
+// sourceRows returns the rows to send. The whole input is validated before the
+// first row is sent, unless it's streamed with constant memory.
+func sourceRows(c *cli.Context, journal *journal) (iter.Seq2[rowItem, error], error) {
+	if !c.Bool("stream") {
+		rows, err := readRows(c, journal)
+		if err != nil {
+			return nil, err
 		}
+
+		return sliceRows(rows), nil
 	}
 
-	skipped := len(rows) - len(results)
-	sent := len(rows) - failed - skipped
-	fmt.Fprintf(
-		os.Stderr,
-		"Batch send summary: total=%d enqueued=%d failed=%d skipped=%d\n",
-		len(rows),
-		sent,
-		failed,
-		skipped,
-	)
+	rows, err := streamRows(c, journal)
+	if err != nil {
+		return nil, cli.Exit(err.Error(), codes.ParamsError)
+	}
 
-	for _, result := range results {
-		if result.Error != nil {
-			continue
-		}
+	return rows, nil
+}
 
-		line, renderErr := renderer.MessageState(result.State)
-		if renderErr != nil {
-			return cli.Exit(renderErr.Error(), codes.OutputError)
+// printState writes the state of an enqueued row to stdout.
+func printState(renderer output.Renderer, state smsgateway.MessageState) error {
+	line, err := renderer.MessageState(state)
+	if err != nil {
+		return fmt.Errorf("render state: %w", err)
+	}
+
+	if _, outErr := fmt.Fprintln(os.Stdout, line); outErr != nil {
+		return fmt.Errorf("write state: %w", outErr)
+	}
+
+	return nil
+}
+
+func printPreview(rows []rowItem) {
+	dropped := 0
+	for _, item := range rows {
+		if item.Dropped != nil {
+			dropped++
 		}
+	}
 
-		if _, outErr := fmt.Fprintln(
-			os.Stdout,
-			line,
-		); outErr != nil {
-			return cli.Exit(outErr.Error(), codes.OutputError)
+	fmt.Fprintf(os.Stderr, "Dry run successful: %d rows%s\n", len(rows)-dropped, droppedSuffix(dropped))
+	for _, item := range rows {
+		row := item.Row
+		row.DeviceID = lo.CoalesceOrEmpty(row.DeviceID, item.Target.DeviceID)
+		switch {
+		case item.Dropped != nil:
+			fmt.Fprintf(
+				os.Stderr,
+				"row=%d id=%q phone=%q dropped=%q\n",
+				row.RowNumber,
+				row.ID,
+				strings.Join(row.Phones, ","),
+				item.Dropped.Error(),
+			)
+		case row.Data != "":
+			fmt.Fprintf(
+				os.Stderr,
+				"row=%d id=%q phone=%q data=%q data_port=%d device_id=%q\n",
+				row.RowNumber,
+				row.ID,
+				strings.Join(row.Phones, ","),
+				row.Data,
+				row.DataPort,
+				row.DeviceID,
+			)
+		default:
+			fmt.Fprintf(
+				os.Stderr,
+				"row=%d id=%q phone=%q text=%q device_id=%q\n",
+				row.RowNumber,
+				row.ID,
+				strings.Join(row.Phones, ","),
+				row.Text,
+				row.DeviceID,
+			)
 		}
 	}
+}
 
-	if failed > 0 {
-		return cli.Exit("one or more rows failed", codes.ClientError)
+// droppedSuffix returns the note about the dropped rows for the preview lines.
+func droppedSuffix(dropped int) string {
+	if dropped == 0 {
+		return ""
 	}
 
-	return nil
+	return fmt.Sprintf(", %d dropped", dropped)
 }
 
-func newTabularReader(c *cli.Context) (tabular.Reader, error) {
-	path := c.Args().Get(0)
-	ext := strings.ToLower(filepath.Ext(path))
-
-	switch ext {
-	case ".csv":
-		delimiter := []rune(c.String("delimiter"))[0]
-		return tabular.NewCSVReader(tabular.CSVConfig{
-			Path:      path,
-			Delimiter: delimiter,
-			HasHeader: c.Bool("header"),
-		}), nil
-	case ".xlsx", ".xlsm":
-		return tabular.NewXLSXReader(tabular.XLSXConfig{
-			Path:      path,
-			Sheet:     c.String("sheet"),
-			HasHeader: c.Bool("header"),
-		}), nil
-	default:
-		return nil, fmt.Errorf("%w: unsupported file extension %q; use .csv or .xlsx", ErrValidationFailed, ext)
+// printSummary writes the batch outcome to stderr.
+func printSummary(stats batchStats) {
+	fmt.Fprintf(
+		os.Stderr,
+		"Batch send summary: total=%d enqueued=%d failed=%d skipped=%d resumed=%d dropped=%d\n",
+		stats.Total,
+		stats.Enqueued,
+		stats.Failed,
+		stats.Skipped,
+		stats.Resumed,
+		stats.Dropped,
+	)
+
+	keys := slices.SortedFunc(maps.Keys(stats.Devices), compareDeviceKeys)
+	for _, key := range keys {
+		counts := stats.Devices[key]
+		fmt.Fprintf(os.Stderr, "  %s: enqueued=%d failed=%d\n", key, counts.Enqueued, counts.Failed)
 	}
 }
 
+// runBatchSend sends the rows with a pool of workers as they are read, printing
+// and journaling every outcome and passing it to onResult. Only the rows in
+// flight are kept in memory. A read or journal write error stops the batch.
 func runBatchSend(
 	ctx context.Context,
 	client *smsgateway.Client,
-	rows []mappings.SendRow,
-	sendFlags *flags.SendFlags,
-	concurrency int,
-	continueOnError bool,
-) []batchRowResult {
+	rows iter.Seq2[rowItem, error],
+	cfg batchConfig,
+	journal *journal,
+	onResult func(batchRowResult),
+) (batchStats, error) {
 	workerCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	jobs := make(chan mappings.SendRow)
-	results := make(chan batchRowResult, len(rows))
+	jobs := make(chan rowItem)
+	results := make(chan batchRowResult, cfg.Concurrency)
 
 	var wg sync.WaitGroup
-	for range concurrency {
+	for range cfg.Concurrency {
 		wg.Go(func() {
-			sendWorker(workerCtx, client, jobs, results, sendFlags, continueOnError, cancel)
+			sendWorker(workerCtx, client, jobs, results, cfg, cancel)
 		})
 	}
 
-	go func() {
+	var readErr error
+	wg.Go(func() {
 		defer close(jobs)
-		for _, row := range rows {
-			if workerCtx.Err() != nil {
-				return
-			}
-			jobs <- row
-		}
-	}()
+		readErr = dispatchRows(workerCtx, rows, jobs, results, journal, cancel)
+	})
 
 	go func() {
 		wg.Wait()
 		close(results)
 	}()
 
-	out := make([]batchRowResult, 0, len(rows))
+	var (
+		stats      batchStats
+		journalErr error
+	)
 	for result := range results {
-		var state string
-		if result.Error != nil {
-			state = fmt.Sprintf("failed: %s", result.Error.Error())
-		} else {
-			state = string(result.State.State)
+		stats.Add(result)
+		if result.Status != statusSkipped && result.Status != statusResumed {
+			printProgress(result, cfg.Retry.Retries > 0)
+		}
+
+		if result.Identifier != "" && (result.Status == statusEnqueued || result.Status == statusFailed) {
+			if err := journal.Record(result); err != nil && journalErr == nil {
+				journalErr = err
+				cancel()
+			}
 		}
-		fmt.Fprintf(os.Stderr, "[%d] %s: %s\n", result.RowNumber, result.Identifier, state)
-		out = append(out, result)
+
+		onResult(result)
+	}
+
+	if readErr != nil {
+		return stats, readErr
 	}
 
-	return out
+	return stats, journalErr
+}
+
+// dispatchRows passes the rows to the workers. Rows enqueued by previous runs
+// are resumed, and once the batch is stopped or interrupted the input is still
+// read to the end and the remaining rows are skipped, so they are still counted,
+// journaled and reported.
+func dispatchRows(
+	workerCtx context.Context,
+	rows iter.Seq2[rowItem, error],
+	jobs chan<- rowItem,
+	results chan<- batchRowResult,
+	journal *journal,
+	cancel context.CancelFunc,
+) error {
+	for item, err := range rows {
+		if err != nil {
+			cancel()
+			return fmt.Errorf("%w: %w", ErrReadInput, err)
+		}
+
+		identifier, resumed := journal.Enqueued(item.Row)
+		if !resumed {
+			item.Identifier = journal.Failed(item.Row)
+			select {
+			case <-workerCtx.Done():
+			case jobs <- item:
+				continue
+			}
+		}
+
+		result := batchRowResult{
+			Row:        item.Row,
+			Identifier: "",
+			Status:     statusSkipped,
+			State:      smsgateway.MessageState{}, //nolint:exhaustruct // not sent
+			Attempts:   0,
+			Error:      nil,
+			Target:     target{DeviceID: "", SimNumber: nil},
+		}
+		if resumed {
+			result.Status = statusResumed
+			result.Identifier = identifier
+		}
+		results <- result
+	}
+
+	return nil
+}
+
+// printProgress writes the outcome of a sent row to stderr.
+func printProgress(result batchRowResult, withAttempts bool) {
+	var state string
+	if result.Error != nil {
+		state = fmt.Sprintf("%s: %s", result.Status, result.Error.Error())
+	} else {
+		state = string(result.State.State)
+	}
+	if withAttempts && result.Identifier != "" {
+		state = fmt.Sprintf("%s (attempts: %d)", state, result.Attempts)
+	}
+
+	if result.Identifier == "" {
+		fmt.Fprintf(os.Stderr, "[%d] %s\n", result.Row.RowNumber, state)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "[%d] %s: %s\n", result.Row.RowNumber, result.Identifier, state)
 }
 
 func sendWorker(
 	ctx context.Context,
 	client *smsgateway.Client,
 
-	jobs <-chan mappings.SendRow,
+	jobs <-chan rowItem,
 	results chan<- batchRowResult,
 
-	sendFlags *flags.SendFlags,
-	continueOnError bool,
+	cfg batchConfig,
 	cancel context.CancelFunc,
 ) {
-	for row := range jobs {
-		identifier := row.ID
-		if identifier == "" {
-			identifier = uuid.Must(uuid.NewV7()).String()
+	for item := range jobs {
+		result := batchRowResult{
+			Row:        item.Row,
+			Identifier: "",
+			Status:     statusSkipped,
+			State:      smsgateway.MessageState{}, //nolint:exhaustruct // not sent
+			Attempts:   0,
+			Error:      nil,
+			Target:     target{DeviceID: "", SimNumber: nil},
 		}
 
-		req := smsgateway.Message{
-			ID:       identifier,
-			DeviceID: row.DeviceID,
-			Message:  "",
-			TextMessage: &smsgateway.TextMessage{
-				Text: row.Text,
-			},
-			DataMessage:        nil,
-			PhoneNumbers:       []string{row.Phone},
-			IsEncrypted:        false,
-			SimNumber:          row.SimNumber,
-			WithDeliveryReport: nil,
-			Priority:           0,
-			TTL:                nil,
-			ValidUntil:         nil,
-			ScheduleAt:         nil,
+		switch {
+		case ctx.Err() != nil:
+			// the batch was stopped, the row is reported as skipped
+		case item.Err != nil:
+			// the row is invalid, it fails without being sent
+			result.Status = statusFailed
+			result.Error = item.Err
+		case item.Dropped != nil:
+			result.Status = statusDropped
+			result.Error = item.Dropped
+		default:
+			result = sendRow(ctx, client, item, cfg)
 		}
 
-		req = sendFlags.Merge(req)
-
-		if row.Priority != nil {
-			req.Priority = smsgateway.MessagePriority(*row.Priority)
+		results <- result
+		if result.Status == statusFailed && !cfg.ContinueOnError {
+			cancel()
 		}
+	}
+}
 
-		options := sendFlags.Option()
+// sendRow sends the row with the batch settings.
+func sendRow(ctx context.Context, client *smsgateway.Client, item rowItem, cfg batchConfig) batchRowResult {
+	req, options := newRequest(item, cfg)
+	result := batchRowResult{
+		Row:        item.Row,
+		Identifier: req.ID,
+		Status:     statusEnqueued,
+		State:      smsgateway.MessageState{}, //nolint:exhaustruct // not sent yet
+		Attempts:   0,
+		Error:      nil,
+		Target:     target{DeviceID: req.DeviceID, SimNumber: req.SimNumber},
+	}
 
-		state, err := client.Send(ctx, req, options...)
-		results <- batchRowResult{RowNumber: row.RowNumber, Identifier: identifier, Error: err, State: state}
-		if err != nil && !continueOnError {
-			cancel()
+	deviceCtx, release, err := cfg.Devices.Acquire(ctx, req.DeviceID)
+	if err != nil {
+		// the batch was stopped while waiting for the device
+		result.Status = statusSkipped
+		result.Identifier = ""
+		result.Target = target{DeviceID: "", SimNumber: nil}
+		return result
+	}
+	defer release()
+
+	sendCtx, counter := retry.WithPolicy(ratelimit.WithLimiter(deviceCtx, cfg.Limiter), cfg.Retry)
+	if cfg.Encryptor != nil {
+		req, err = cfg.Encryptor.EncryptMessage(req)
+	}
+	if err == nil {
+		result.State, err = client.Send(sendCtx, req, options...)
+	}
+	if err == nil && cfg.Encryptor != nil {
+		// the message is already enqueued, keep the state as is if it can't be decrypted
+		if decrypted, decErr := cfg.Encryptor.DecryptState(result.State); decErr == nil {
+			result.State = decrypted
 		}
 	}
+
+	result.Attempts = counter.Attempts()
+	if err != nil {
+		result.Status = statusFailed
+		result.Error = err
+	}
+
+	return result
+}
+
+// newRequest builds the message of the row. The row values take precedence
+// over the assigned device and SIM, which take precedence over the flags.
+func newRequest(item rowItem, cfg batchConfig) (smsgateway.Message, []smsgateway.SendOption) {
+	row := item.Row
+	identifier := lo.CoalesceOrEmpty(row.ID, item.Identifier)
+	if identifier == "" {
+		identifier = uuid.Must(uuid.NewV7()).String()
+	}
+
+	req := smsgateway.Message{
+		ID:                 identifier,
+		DeviceID:           lo.CoalesceOrEmpty(row.DeviceID, item.Target.DeviceID),
+		Message:            "",
+		TextMessage:        &smsgateway.TextMessage{Text: row.Text},
+		DataMessage:        nil,
+		PhoneNumbers:       row.Phones,
+		IsEncrypted:        false,
+		SimNumber:          lo.CoalesceOrEmpty(row.SimNumber, item.Target.SimNumber),
+		WithDeliveryReport: row.DeliveryReport,
+		Priority:           0,
+		TTL:                row.TTL,
+		ValidUntil:         row.ValidUntil,
+		ScheduleAt:         row.ScheduleAt,
+	}
+
+	if row.Data != "" {
+		req.TextMessage = nil
+		req.DataMessage = &smsgateway.DataMessage{Data: row.Data, Port: row.DataPort}
+	}
+
+	req = cfg.SendFlags.Merge(req)
+	// a per-row expiration replaces the one set by the flags
+	switch {
+	case row.TTL != nil:
+		req.ValidUntil = nil
+	case row.ValidUntil != nil:
+		req.TTL = nil
+	}
+
+	if row.Priority != nil {
+		req.Priority = smsgateway.MessagePriority(*row.Priority)
+	}
+
+	options := cfg.SendFlags.Option()
+	if row.SkipPhoneValidation != nil {
+		options = append(options, smsgateway.WithSkipPhoneValidation(*row.SkipPhoneValidation))
+	}
+
+	return req, options
 }
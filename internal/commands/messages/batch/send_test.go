@@ -4,6 +4,7 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/android-sms-gateway/cli/internal/commands/messages"
@@ -63,6 +64,42 @@ func TestBatchSend_InvalidMap(t *testing.T) {
 	assert.Contains(t, err.Error(), "map must include phone=<column>")
 }
 
+func TestBatchSend_Template(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.write(t, "input.csv", "Phone,FirstName,Code\n+12025550123,Ann,1234\n+12025550124,,5678\n+12025550125,Bob,\n")
+
+	err := f.run(t,
+		"--map", "phone=Phone",
+		"--template", "Hello {{.FirstName}}, your code is {{.Code}}",
+		"--validate-only",
+		path,
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "row 3: validation failed: missing template variables: FirstName")
+	assert.Contains(t, err.Error(), "row 4: validation failed: missing template variables: Code")
+	assert.NotContains(t, err.Error(), "row 2")
+}
+
+func TestBatchSend_TemplateWithTextMapping(t *testing.T) {
+	t.Parallel()
+
+	path, cmd, ok := newBatchSendFixture(t)
+	require.True(t, ok)
+
+	ctx := newContext(t, cmd, []string{
+		"--map", "phone=Phone,text=Message",
+		"--template", "Hello",
+		"--validate-only",
+		path,
+	})
+
+	err := cmd.Before(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "can't be combined with a message template")
+}
+
 func findBatchSendCommand(cmds []*cli.Command) (*cli.Command, bool) {
 	for _, cmd := range cmds {
 		if cmd.Name != "batch" {
@@ -89,3 +126,33 @@ func newContext(t *testing.T, cmd *cli.Command, args []string) *cli.Context {
 
 	return cli.NewContext(&cli.App{}, set, nil)
 }
+
+func TestBatchSend_InvalidRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{args: []string{"--rate", "10"}, err: "expected N/s, N/m or N/h"},
+		{args: []string{"--rate", "0/s"}, err: "count must be a positive number"},
+		{args: []string{"--rate", "10/d"}, err: "unit must be s, m or h"},
+		{args: []string{"--rate", "10/s", "--burst", "0"}, err: "burst must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			t.Parallel()
+
+			path, cmd, ok := newBatchSendFixture(t)
+			require.True(t, ok)
+
+			args := append([]string{"--map", "phone=Phone,text=Message"}, tt.args...)
+			ctx := newContext(t, cmd, append(args, path))
+
+			err := cmd.Before(ctx)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
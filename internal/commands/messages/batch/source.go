@@ -0,0 +1,165 @@
+package batch
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+	"time"
+
+	"github.com/android-sms-gateway/cli/internal/commands/flags"
+	"github.com/android-sms-gateway/cli/internal/commands/messages/batch/mappings"
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/urfave/cli/v2"
+)
+
+// rowItem is a row mapped from an input record, the validation error of the
+// record, or a row dropped by the suppressor with the reason.
+type rowItem struct {
+	Row     mappings.SendRow
+	Err     error
+	Dropped error
+	// Target is the device and SIM assigned by the distributor.
+	Target target
+	// Identifier is the message ID of a failed attempt by a previous run, reused
+	// when the row has no ID of its own.
+	Identifier string
+}
+
+// streamRows reads and maps the input records one at a time. The iteration
+// stops with an error when the input can't be read. Rows the journal marks as
+// enqueued skip the time checks, as they aren't sent again.
+func streamRows(c *cli.Context, journal *journal) (iter.Seq2[rowItem, error], error) {
+	reader, err := newTabularReader(c)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := flags.NewTemplate(c)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationFailed, err)
+	}
+
+	normalizer, err := flags.NewPhoneNormalizer(c)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationFailed, err)
+	}
+
+	// the flags honor the profile default of skip-phone-validation
+	sendFlags, err := flags.NewSendFlags(c)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationFailed, err)
+	}
+
+	suppressor, err := newSuppressor(c)
+	if err != nil {
+		return nil, err
+	}
+
+	distributor, err := newDistributor(c)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, _ := mappings.ParseColumnMapping(c.String("map"))
+	dataPort, _ := flags.DataPort(c)
+	opts := mappings.Options{
+		Template:            tmpl,
+		Data:                c.Bool("data"),
+		DataPort:            dataPort,
+		PhoneSeparator:      c.String("phone-separator"),
+		SplitRecipients:     c.Bool("split-recipients"),
+		Phones:              normalizer,
+		SkipPhoneValidation: sendFlags.SkipPhoneValidation,
+	}
+
+	return func(yield func(rowItem, error) bool) {
+		for record, readErr := range reader.Records(c.Context) {
+			if readErr != nil {
+				yield(rowItem{}, readErr)
+				return
+			}
+
+			rows, mapErr := mappings.MapRecord(record, mapping, opts)
+			if mapErr != nil {
+				//nolint:exhaustruct // the row is invalid, only its identity is reported
+				invalid := mappings.SendRow{
+					RowNumber: record.RowNumber,
+					ID:        strings.TrimSpace(record.Values[mapping["id"]]),
+				}
+				item := rowItem{
+					Row:        invalid,
+					Err:        mapErr,
+					Dropped:    nil,
+					Target:     target{DeviceID: "", SimNumber: nil},
+					Identifier: "",
+				}
+				if !yield(item, nil) {
+					return
+				}
+				continue
+			}
+
+			for _, row := range rows {
+				for _, item := range suppressor.Filter(row) {
+					if _, resumed := journal.Enqueued(item.Row); item.Dropped == nil && !resumed {
+						item.Err = mappings.CheckTimes(item.Row, time.Now())
+					}
+					if item.Dropped == nil && item.Err == nil {
+						item.Target = distributor.Assign(item.Row)
+					}
+					if !yield(item, nil) {
+						return
+					}
+				}
+			}
+		}
+	}, nil
+}
+
+// scanRows validates the whole input, calling fn for every valid or dropped
+// row. All validation errors are reported at once.
+func scanRows(c *cli.Context, journal *journal, fn func(rowItem)) error {
+	rows, err := streamRows(c, journal)
+	if err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+
+	errs := make([]string, 0)
+	for item, readErr := range rows {
+		if readErr != nil {
+			return cli.Exit(readErr.Error(), codes.ParamsError)
+		}
+		if item.Err != nil {
+			errs = append(errs, fmt.Sprintf("row %d: %v", item.Row.RowNumber, item.Err))
+			continue
+		}
+		fn(item)
+	}
+
+	if len(errs) > 0 {
+		return cli.Exit(strings.Join(errs, "\n"), codes.ParamsError)
+	}
+
+	return nil
+}
+
+// readRows reads and validates the whole input into memory.
+func readRows(c *cli.Context, journal *journal) ([]rowItem, error) {
+	rows := make([]rowItem, 0)
+	if err := scanRows(c, journal, func(item rowItem) { rows = append(rows, item) }); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// sliceRows yields the validated rows.
+func sliceRows(rows []rowItem) iter.Seq2[rowItem, error] {
+	return func(yield func(rowItem, error) bool) {
+		for _, item := range rows {
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package batch_test
+
+import (
+	"testing"
+
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+const streamInput = "ID,Phone,Message\n" +
+	"msg-1,+12025550123,Hello\n" +
+	"msg-2,not-a-phone,Hello\n" +
+	"msg-3,+12025550125,Hello\n"
+
+func TestBatchSend_StreamInvalidRow(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		sent   []string
+		status []string
+	}{
+		{
+			name:   "stop",
+			args:   nil,
+			sent:   []string{"+12025550123"},
+			status: []string{"enqueued", "failed", "skipped"},
+		},
+		{
+			name:   "continue on error",
+			args:   []string{"--continue-on-error"},
+			sent:   []string{"+12025550123", "+12025550125"},
+			status: []string{"enqueued", "failed", "enqueued"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := newSendFixture(t, &sendServer{})
+			path := f.write(t, "input.csv", streamInput)
+			reportPath := f.path("report.csv")
+
+			args := append([]string{
+				"--map", "id=ID,phone=Phone,text=Message",
+				"--stream",
+				"--concurrency", "1",
+				"--report", reportPath,
+			}, tt.args...)
+			err := f.run(t, append(args, path)...)
+			require.Error(t, err)
+			var exitErr cli.ExitCoder
+			require.ErrorAs(t, err, &exitErr)
+			assert.Equal(t, codes.ClientError, exitErr.ExitCode())
+
+			assert.Equal(t, tt.sent, f.server.sent())
+
+			rows := readReport(t, reportPath)
+			require.Len(t, rows, len(tt.status))
+			for i, status := range tt.status {
+				assert.Equal(t, status, rows[i]["status"], "row %s", rows[i]["row"])
+			}
+			assert.Equal(t, "msg-2", rows[1]["id"])
+			assert.Contains(t, rows[1]["error"], "invalid phone number")
+		})
+	}
+}
+
+func TestBatchSend_ValidateFirst(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.write(t, "input.csv", streamInput)
+
+	err := f.run(t,
+		"--map", "id=ID,phone=Phone,text=Message",
+		"--continue-on-error",
+		path,
+	)
+	require.Error(t, err)
+	var exitErr cli.ExitCoder
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, codes.ParamsError, exitErr.ExitCode())
+	assert.Contains(t, err.Error(), "row 3: ")
+
+	assert.Empty(t, f.server.sent())
+}
@@ -0,0 +1,137 @@
+package batch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/android-sms-gateway/cli/internal/commands/flags"
+	"github.com/android-sms-gateway/cli/internal/commands/messages/batch/mappings"
+	"github.com/android-sms-gateway/cli/internal/config"
+	"github.com/android-sms-gateway/cli/internal/optout"
+	"github.com/android-sms-gateway/cli/internal/phones"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	dedupePhone     = "phone"
+	dedupePhoneText = "phone+text"
+)
+
+// suppressor drops the recipients that opted out and, with deduplication,
+// the recipients already messaged earlier in the batch. The numbers are matched
+// by their canonical form, also when phone validation is skipped.
+type suppressor struct {
+	dedupe     string
+	normalizer *phones.Normalizer
+	// suppressed are the canonical opted-out phone numbers.
+	suppressed map[string]struct{}
+	// seen maps the deduplication keys to the number of the first row.
+	seen map[string]int
+}
+
+// newSuppressor loads the opt-out store and the --suppress-list file. Invalid
+// numbers of the file are skipped with a warning, so one bad entry doesn't stop
+// the batch. National numbers are read in the default region; without one they
+// only match the same national numbers of unvalidated rows.
+func newSuppressor(c *cli.Context) (*suppressor, error) {
+	path, err := config.ResolvePath(c.String("config"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationFailed, err)
+	}
+
+	normalizer, err := flags.NewPhoneNormalizer(c)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationFailed, err)
+	}
+
+	// the stored numbers are in the E.164 format, which is canonical
+	suppressed, err := optout.NewStore(filepath.Dir(path)).Phones()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationFailed, err)
+	}
+
+	if listPath := c.String("suppress-list"); listPath != "" {
+		numbers, readErr := optout.ReadCSV(c.Context, listPath, "phone")
+		if readErr != nil {
+			return nil, fmt.Errorf("%w: suppress list: %w", ErrValidationFailed, readErr)
+		}
+		for _, number := range numbers {
+			if _, phoneErr := normalizer.Normalize(number); phoneErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: suppress list: skipped %s\n", phoneErr)
+				continue
+			}
+			suppressed[normalizer.Canonical(number)] = struct{}{}
+		}
+	}
+
+	return &suppressor{
+		dedupe:     c.String("dedupe"),
+		normalizer: normalizer,
+		suppressed: suppressed,
+		seen:       map[string]int{},
+	}, nil
+}
+
+// Filter returns an item for every dropped recipient of the row with the
+// reason, followed by the row with the recipients left, if any.
+func (s *suppressor) Filter(row mappings.SendRow) []rowItem {
+	items := make([]rowItem, 0, 1)
+	kept := make([]string, 0, len(row.Phones))
+	for _, phone := range row.Phones {
+		reason := s.check(row, phone)
+		if reason == nil {
+			kept = append(kept, phone)
+			continue
+		}
+
+		dropped := row
+		dropped.Phones = []string{phone}
+		items = append(items, rowItem{
+			Row:        dropped,
+			Err:        nil,
+			Dropped:    reason,
+			Target:     target{DeviceID: "", SimNumber: nil},
+			Identifier: "",
+		})
+	}
+
+	if len(kept) > 0 {
+		row.Phones = kept
+		items = append(items, rowItem{
+			Row:        row,
+			Err:        nil,
+			Dropped:    nil,
+			Target:     target{DeviceID: "", SimNumber: nil},
+			Identifier: "",
+		})
+	}
+
+	return items
+}
+
+// check returns the reason to drop the recipient of the row, nil to send it.
+func (s *suppressor) check(row mappings.SendRow, phone string) error {
+	canonical := s.normalizer.Canonical(phone)
+	if _, ok := s.suppressed[canonical]; ok {
+		return fmt.Errorf("%w: %s opted out", ErrSuppressed, phone)
+	}
+
+	var key string
+	switch s.dedupe {
+	case dedupePhone:
+		key = canonical
+	case dedupePhoneText:
+		key = strings.Join([]string{canonical, row.Text, row.Data}, "\x00")
+	default:
+		return nil
+	}
+
+	if first, ok := s.seen[key]; ok {
+		return fmt.Errorf("%w of row %d", ErrDuplicate, first)
+	}
+	s.seen[key] = row.RowNumber
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package batch_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dedupeInput = "ID,Phone,Message\n" +
+	"msg-1,+12025550123,Hello\n" +
+	"msg-2,+12025550123,Hello\n" +
+	"msg-3,+12025550123,Bye\n" +
+	"msg-4,\"+12025550124, +12025550123\",Hello\n"
+
+func TestBatchSend_Dedupe(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		dedupe string
+		sent   []string
+		report []map[string]string
+	}{
+		{
+			dedupe: "phone",
+			sent:   []string{"+12025550123", "+12025550124"},
+			report: []map[string]string{
+				{"row": "2", "id": "msg-1", "phone": "+12025550123", "status": "enqueued", "error": ""},
+				{"row": "3", "id": "msg-2", "phone": "+12025550123", "status": "dropped", "error": "duplicate of row 2"},
+				{"row": "4", "id": "msg-3", "phone": "+12025550123", "status": "dropped", "error": "duplicate of row 2"},
+				{"row": "5", "id": "msg-4", "phone": "+12025550123", "status": "dropped", "error": "duplicate of row 2"},
+				{"row": "5", "id": "msg-4", "phone": "+12025550124", "status": "enqueued", "error": ""},
+			},
+		},
+		{
+			dedupe: "phone+text",
+			sent:   []string{"+12025550123", "+12025550123", "+12025550124"},
+			report: []map[string]string{
+				{"row": "2", "id": "msg-1", "phone": "+12025550123", "status": "enqueued", "error": ""},
+				{"row": "3", "id": "msg-2", "phone": "+12025550123", "status": "dropped", "error": "duplicate of row 2"},
+				{"row": "4", "id": "msg-3", "phone": "+12025550123", "status": "enqueued", "error": ""},
+				{"row": "5", "id": "msg-4", "phone": "+12025550123", "status": "dropped", "error": "duplicate of row 2"},
+				{"row": "5", "id": "msg-4", "phone": "+12025550124", "status": "enqueued", "error": ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dedupe, func(t *testing.T) {
+			t.Parallel()
+
+			f := newSendFixture(t, &sendServer{})
+			path := f.write(t, "input.csv", dedupeInput)
+			reportPath := f.path("report.csv")
+
+			require.NoError(t, f.run(t,
+				"--map", "id=ID,phone=Phone,text=Message",
+				"--dedupe", tt.dedupe,
+				"--concurrency", "1",
+				"--report", reportPath,
+				path,
+			))
+
+			assert.Equal(t, tt.sent, f.server.sent())
+
+			rows := readReport(t, reportPath)
+			require.Len(t, rows, len(tt.report))
+			for i, want := range tt.report {
+				for column, value := range want {
+					assert.Equal(t, value, rows[i][column], "report line %d, column %s", i+1, column)
+				}
+			}
+		})
+	}
+}
+
+func TestBatchSend_SuppressList(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.write(t, "input.csv", "Phone,Message\n+12025550123,Hello\n(202) 555-0124,Hello\n+12025550125,Hello\n")
+	listPath := f.write(t, "suppress.csv", "Name,Phone\nBob,202-555-0124\n")
+
+	require.NoError(t, f.run(t,
+		"--map", "phone=Phone,text=Message",
+		"--default-region", "US",
+		"--suppress-list", listPath,
+		path,
+	))
+
+	assert.ElementsMatch(t, []string{"+12025550123", "+12025550125"}, f.server.sent())
+}
+
+func TestBatchSend_SuppressUnvalidated(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.write(t, "input.csv",
+		"Phone,Message\n+12025550123,Hello\n+1 (202) 555-0123,Hello\n+1 202.555.0124,Hello\n",
+	)
+	listPath := f.write(t, "suppress.csv", "Phone\n+1 202 555 0124\n")
+
+	require.NoError(t, f.run(t,
+		"--map", "phone=Phone,text=Message",
+		"--skip-phone-validation",
+		"--dedupe", "phone",
+		"--suppress-list", listPath,
+		path,
+	))
+
+	assert.Equal(t, []string{"+12025550123"}, f.server.sent())
+}
+
+func TestBatchSend_SuppressInvalidEntries(t *testing.T) {
+	t.Parallel()
+
+	f := newSendFixture(t, &sendServer{})
+	path := f.writePhones(t, "+12025550123", "+12025550124", "2025550125")
+	listPath := f.write(t, "suppress.csv", "Phone\nnot a phone\n+12025550124\n+1 202 555\n(202) 555-0125\n")
+
+	// the invalid entries are skipped, the national one is read in the default region
+	require.NoError(t, f.run(t,
+		"--map", "phone=Phone,text=Message",
+		"--default-region", "US",
+		"--suppress-list", listPath,
+		path,
+	))
+
+	assert.Equal(t, []string{"+12025550123"}, f.server.sent())
+}
+
+func TestBatchSend_InvalidSuppression(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "unknown dedupe mode",
+			args:    []string{"--dedupe", "text"},
+			wantErr: "dedupe must be one of: phone, phone+text",
+		},
+		{
+			name:    "missing suppress list",
+			args:    []string{"--suppress-list", filepath.Join(dir, "missing.csv")},
+			wantErr: "suppress list: read",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path, cmd, ok := newBatchSendFixture(t)
+			require.True(t, ok)
+
+			args := append([]string{"--map", "phone=Phone,text=Message", "--default-region", "US"}, tt.args...)
+			ctx := newContext(t, cmd, append(args, "--validate-only", path))
+
+			err := cmd.Before(ctx)
+			if err == nil {
+				err = cmd.Action(ctx)
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
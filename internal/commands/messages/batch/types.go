@@ -1,10 +1,103 @@
 package batch
 
-import "github.com/android-sms-gateway/client-go/smsgateway"
+import (
+	"github.com/android-sms-gateway/cli/internal/commands/flags"
+	"github.com/android-sms-gateway/cli/internal/commands/messages/batch/mappings"
+	"github.com/android-sms-gateway/cli/internal/core/ratelimit"
+	"github.com/android-sms-gateway/cli/internal/core/retry"
+	"github.com/android-sms-gateway/cli/internal/encryption"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+// batchConfig holds the settings shared by the send workers.
+type batchConfig struct {
+	SendFlags       *flags.SendFlags
+	Encryptor       *encryption.Encryptor
+	Concurrency     int
+	ContinueOnError bool
+	// Limiter throttles the requests of all workers and slows them down on 429 responses.
+	Limiter *ratelimit.Limiter
+	// Retry repeats sends failed with transient errors.
+	Retry retry.Policy
+	// Devices caps the concurrent sends and the send rate of every device.
+	Devices *deviceLimits
+}
+
+// rateLimitRetries is the number of times a send throttled with 429 Too Many
+// Requests is repeated, so large batches slow down instead of failing partway.
+const rateLimitRetries = 5
+
+const (
+	statusEnqueued = "enqueued"
+	statusFailed   = "failed"
+	// statusSkipped is the status of rows not sent because the batch was stopped.
+	statusSkipped = "skipped"
+	// statusResumed is the status of rows enqueued by previous runs according to the journal.
+	statusResumed = "resumed"
+	// statusDropped is the status of duplicate and opted-out rows.
+	statusDropped = "dropped"
+)
 
 type batchRowResult struct {
-	RowNumber  int
+	Row        mappings.SendRow
 	Identifier string
+	Status     string
 	State      smsgateway.MessageState
-	Error      error
+	// Attempts is the number of send requests made for the row.
+	Attempts int
+	Error    error
+	// Target is the device and SIM the row was sent with.
+	Target target
+}
+
+// batchStats counts the rows by status.
+type batchStats struct {
+	Total    int
+	Enqueued int
+	Failed   int
+	Skipped  int
+	Resumed  int
+	Dropped  int
+	// Devices counts the sent rows by device and SIM.
+	Devices map[deviceKey]*deviceCounts
+}
+
+// deviceCounts counts the rows sent with a device and SIM.
+type deviceCounts struct {
+	Enqueued int
+	Failed   int
+}
+
+func (s *batchStats) Add(result batchRowResult) {
+	s.Total++
+	switch result.Status {
+	case statusEnqueued:
+		s.Enqueued++
+	case statusFailed:
+		s.Failed++
+	case statusSkipped:
+		s.Skipped++
+	case statusResumed:
+		s.Resumed++
+	case statusDropped:
+		s.Dropped++
+	}
+
+	if result.Target.DeviceID == "" && result.Target.SimNumber == nil {
+		return
+	}
+	if s.Devices == nil {
+		s.Devices = map[deviceKey]*deviceCounts{}
+	}
+	counts, ok := s.Devices[result.Target.key()]
+	if !ok {
+		counts = &deviceCounts{Enqueued: 0, Failed: 0}
+		s.Devices[result.Target.key()] = counts
+	}
+	switch result.Status {
+	case statusEnqueued:
+		counts.Enqueued++
+	case statusFailed:
+		counts.Failed++
+	}
 }
@@ -0,0 +1,57 @@
+package messages
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+const stdinSource = "-"
+
+// bodySource returns the file to read the message content from ("-" for stdin)
+// or an empty string when the content is passed as an argument.
+func bodySource(c *cli.Context) string {
+	if c.IsSet("file") {
+		return c.String("file")
+	}
+	if c.Args().Get(0) == stdinSource {
+		return stdinSource
+	}
+
+	return ""
+}
+
+// readBody returns the message content. Content read from a file or stdin
+// is base64 encoded for data messages, trailing line breaks are trimmed for text.
+func readBody(c *cli.Context) (string, error) {
+	source := bodySource(c)
+	if source == "" {
+		return c.Args().Get(0), nil
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+	if source == stdinSource {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read message content: %w", err)
+	}
+
+	if c.Bool("data") {
+		if len(b) == 0 {
+			return "", nil
+		}
+		return base64.StdEncoding.EncodeToString(b), nil
+	}
+
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
@@ -0,0 +1,29 @@
+package messages
+
+import (
+	"fmt"
+
+	"github.com/android-sms-gateway/cli/internal/commands/flags"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/urfave/cli/v2"
+)
+
+// decryptStates decrypts the recipients of encrypted messages for display
+// when --passphrase is set.
+func decryptStates(c *cli.Context, states []smsgateway.MessageState) ([]smsgateway.MessageState, error) {
+	encryptor := flags.NewEncryptor(c)
+	if encryptor == nil {
+		return states, nil
+	}
+
+	decrypted := make([]smsgateway.MessageState, len(states))
+	for i, state := range states {
+		d, err := encryptor.DecryptState(state)
+		if err != nil {
+			return nil, fmt.Errorf("message %s: %w", state.ID, err)
+		}
+		decrypted[i] = d
+	}
+
+	return decrypted, nil
+}
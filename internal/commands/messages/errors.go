@@ -0,0 +1,7 @@
+package messages
+
+import "errors"
+
+var (
+	ErrStdinReused = errors.New("stdin can be read only once")
+)
@@ -0,0 +1,206 @@
+package messages
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/android-sms-gateway/cli/internal/commands/flags"
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	periodflags "github.com/android-sms-gateway/cli/internal/flags"
+	"github.com/android-sms-gateway/cli/internal/utils/metadata"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// listPageSize is the number of messages requested per page when --limit is not set.
+	listPageSize = 100
+	// listMaxPages stops automatic paging of a server that never returns an empty page.
+	listMaxPages = 1000
+)
+
+func historyCmd() *cli.Command {
+	return &cli.Command{
+		Name:     "messages",
+		Aliases:  []string{"message", "msg"},
+		Usage:    "Browse sent messages",
+		Category: "Messages",
+		Subcommands: []*cli.Command{
+			listCmd(),
+		},
+	}
+}
+
+func listCmd() *cli.Command {
+	f := periodflags.Period()
+	f = append(f,
+		&cli.StringFlag{
+			Name:  "state",
+			Usage: "Only messages in the state: Pending, Processed, Sent, Delivered or Failed",
+		},
+		&cli.StringFlag{
+			Name:    "device-id",
+			Aliases: []string{"device"},
+			Usage:   "Only messages sent by the device",
+		},
+		&cli.IntFlag{
+			Name:  "limit",
+			Usage: "Maximum number of messages to return (0 to fetch all pages)",
+			Value: 0,
+		},
+		&cli.IntFlag{
+			Name:  "offset",
+			Usage: "Number of messages to skip",
+			Value: 0,
+		},
+	)
+	f = append(f, flags.Encryption()...)
+
+	return &cli.Command{
+		Name:     "list",
+		Aliases:  []string{"l", "ls"},
+		Usage:    "List sent messages and their recipient states for a time range",
+		Category: "Messages",
+		Flags:    f,
+		Before:   listBefore,
+		Action:   listAction,
+	}
+}
+
+func listBefore(c *cli.Context) error {
+	if err := periodflags.ValidatePeriod(c); err != nil {
+		return err
+	}
+
+	if _, ok := parseState(c.String("state")); !ok {
+		return cli.Exit(fmt.Sprintf("State must be one of: %s", joinStates()), codes.ParamsError)
+	}
+	if c.Int("limit") < 0 {
+		return cli.Exit("Limit must not be negative", codes.ParamsError)
+	}
+	if c.Int("offset") < 0 {
+		return cli.Exit("Offset must not be negative", codes.ParamsError)
+	}
+
+	return nil
+}
+
+func listAction(c *cli.Context) error {
+	period := periodflags.ParsePeriodFlags(c)
+	state, _ := parseState(c.String("state"))
+
+	client := metadata.GetClient(c.App.Metadata)
+	renderer := metadata.GetRenderer(c.App.Metadata)
+
+	req := smsgateway.MessagesListRequest{
+		From:     period.From,
+		To:       period.To,
+		State:    state,
+		DeviceID: c.String("device-id"),
+		Limit:    c.Int("limit"),
+		Offset:   c.Int("offset"),
+	}
+
+	var (
+		states []smsgateway.MessageState
+		err    error
+	)
+	if req.Limit > 0 {
+		states, err = client.ListMessages(c.Context, req)
+	} else {
+		states, err = listAll(c, client, req)
+	}
+	if err != nil {
+		return cli.Exit(err.Error(), codes.ClientError)
+	}
+
+	if states == nil {
+		states = []smsgateway.MessageState{}
+	}
+
+	states, err = decryptStates(c, states)
+	if err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+
+	s, err := renderer.MessageStates(states)
+	if err != nil {
+		return cli.Exit(err.Error(), codes.OutputError)
+	}
+	fmt.Fprintln(os.Stdout, s)
+
+	return nil
+}
+
+// listAll requests pages of listPageSize messages starting at the request offset
+// until an empty page is returned or listMaxPages pages are fetched. A short page
+// doesn't end the listing, as the server may cap the page size.
+func listAll(
+	c *cli.Context,
+	client *smsgateway.Client,
+	req smsgateway.MessagesListRequest,
+) ([]smsgateway.MessageState, error) {
+	states := []smsgateway.MessageState{}
+	req.Limit = listPageSize
+
+	short, warned := false, false
+	for range listMaxPages {
+		page, err := client.ListMessages(c.Context, req)
+		if err != nil {
+			return nil, fmt.Errorf("list messages at offset %d: %w", req.Offset, err)
+		}
+		if len(page) == 0 {
+			return states, nil
+		}
+
+		if short && !warned {
+			warned = true
+			fmt.Fprintf(os.Stderr, "Warning: the server returns fewer than %d messages per page\n", listPageSize)
+		}
+		short = len(page) < listPageSize
+
+		states = append(states, page...)
+		req.Offset += len(page)
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: stopped after %d pages, use --offset to continue\n", listMaxPages)
+
+	return states, nil
+}
+
+// parseState matches the state case-insensitively; an empty value means any state.
+func parseState(value string) (smsgateway.ProcessingState, bool) {
+	if value == "" {
+		return "", true
+	}
+
+	for _, state := range listStates() {
+		if strings.EqualFold(value, string(state)) {
+			return state, true
+		}
+	}
+
+	return "", false
+}
+
+func joinStates() string {
+	states := listStates()
+	names := make([]string, len(states))
+	for i, state := range states {
+		names[i] = string(state)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// listStates returns the states accepted by --state.
+func listStates() []smsgateway.ProcessingState {
+	return []smsgateway.ProcessingState{
+		smsgateway.ProcessingStatePending,
+		smsgateway.ProcessingStateProcessed,
+		smsgateway.ProcessingStateSent,
+		smsgateway.ProcessingStateDelivered,
+		smsgateway.ProcessingStateFailed,
+	}
+}
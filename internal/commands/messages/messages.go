@@ -9,6 +9,7 @@ func Commands() []*cli.Command {
 	return []*cli.Command{
 		sendCmd(),
 		statusCmd(),
+		historyCmd(),
 		batch.Commands(),
 	}
 }
@@ -8,14 +8,15 @@ import (
 
 	"github.com/android-sms-gateway/cli/internal/commands/flags"
 	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/core/retry"
+	"github.com/android-sms-gateway/cli/internal/phones"
 	"github.com/android-sms-gateway/cli/internal/utils/metadata"
 	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/google/uuid"
 	"github.com/urfave/cli/v2"
 )
 
 func sendCmd() *cli.Command {
-	const defaultDataPort = 53739
-
 	fl := []cli.Flag{
 		// Body fields
 		&cli.StringFlag{
@@ -24,36 +25,61 @@ func sendCmd() *cli.Command {
 			Usage:    "Message ID",
 			Required: false,
 		},
+		&cli.StringFlag{
+			Name:     "file",
+			Category: "Body",
+			Usage: "Read message content from file ('-' for stdin); " +
+				"for data messages the raw content is base64 encoded automatically",
+			Required: false,
+		},
 		&cli.StringSliceFlag{
 			Name:     "phones",
 			Category: "Body",
 			Aliases:  []string{"p", "phone"},
-			Usage:    "Phone numbers (E.164 format, e.g. +19162255887)",
+			Usage:    "Phone numbers (E.164 format, e.g. +19162255887, or national format with --default-region)",
 			Required: true,
 		},
+	}
+	fl = append(fl, flags.Phone()...)
+	fl = append(fl, flags.Data()...)
+	fl = append(fl, flags.Send()...)
+	fl = append(fl, flags.Template()...)
+	fl = append(fl, flags.Encryption()...)
+	fl = append(fl, flags.Retry()...)
+	fl = append(fl,
+		&cli.GenericFlag{
+			Name:     "var",
+			Category: "Template",
+			Usage:    "Template variable as key=value, can be repeated",
+			Value:    flags.Vars{},
+		},
 
-		// Data Message
+		// Wait
 		&cli.BoolFlag{
-			Name:     "data",
-			Category: "Data Message",
-			Usage:    "Send data message instead of text, content should be base64 encoded",
+			Name:     "wait",
+			Category: "Wait",
+			Usage:    "Wait until the message reaches the final state, printing every state transition",
 			Value:    false,
 		},
-		&cli.UintFlag{
-			Name:     "data-port",
-			Category: "Data Message",
-			Aliases:  []string{"dataPort"},
-			Usage:    "Destination port for data message (1 to 65535)",
-			Value:    defaultDataPort,
+		&cli.DurationFlag{
+			Name:     "wait-timeout",
+			Category: "Wait",
+			Usage:    "Maximum time to wait for the final state",
+			Value:    defaultWaitTimeout,
 		},
-	}
-	fl = append(fl, flags.Send()...)
+		&cli.StringFlag{
+			Name:     "until",
+			Category: "Wait",
+			Usage:    "Target state to wait for: sent or delivered",
+			Value:    untilDelivered,
+		},
+	)
 
 	return &cli.Command{
 		Name:      "send",
 		Usage:     "Send message",
 		Args:      true,
-		ArgsUsage: "Message content",
+		ArgsUsage: "Message content ('-' to read from stdin)",
 		Category:  "Messages",
 		Flags:     fl,
 		Before:    sendBefore,
@@ -62,16 +88,45 @@ func sendCmd() *cli.Command {
 }
 
 func sendBefore(c *cli.Context) error {
+	if c.IsSet("file") && c.Args().Present() {
+		return cli.Exit("Message content argument and --file are mutually exclusive", codes.ParamsError)
+	}
+	if flags.HasTemplate(c) {
+		if c.IsSet("file") || c.Args().Present() {
+			return cli.Exit("Message template can't be combined with content argument or --file", codes.ParamsError)
+		}
+		if c.Bool("data") {
+			return cli.Exit("Message template can't be used with data messages", codes.ParamsError)
+		}
+	}
+
+	if until := c.String("until"); until != untilSent && until != untilDelivered {
+		return cli.Exit("Until must be one of: sent, delivered", codes.ParamsError)
+	}
+	if c.Bool("wait") && c.Duration("wait-timeout") <= 0 {
+		return cli.Exit("Wait timeout must be greater than 0", codes.ParamsError)
+	}
+	if _, err := flags.NewRetryPolicy(c); err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+	if _, err := flags.NewPhoneNormalizer(c); err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+
 	isDataMessage := c.Bool("data")
 	if !isDataMessage {
 		return nil
 	}
 
-	dataPort := c.Uint("data-port")
-	if dataPort < 1 || dataPort > 65535 {
+	if _, ok := flags.DataPort(c); !ok {
 		return cli.Exit("Data port must be between 1 and 65535", codes.ParamsError)
 	}
 
+	// content read from a file or stdin is encoded in sendAction
+	if bodySource(c) != "" {
+		return nil
+	}
+
 	data := strings.TrimSpace(c.Args().Get(0))
 	if data == "" {
 		return cli.Exit("Message is empty", codes.ParamsError)
@@ -86,25 +141,53 @@ func sendBefore(c *cli.Context) error {
 }
 
 func sendAction(c *cli.Context) error {
-	msg := c.Args().Get(0)
+	tmpl, err := flags.NewTemplate(c)
+	if err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+
+	var msg string
+	if tmpl != nil {
+		vars, _ := c.Generic("var").(flags.Vars)
+		msg, err = tmpl.Execute(vars)
+	} else {
+		msg, err = readBody(c)
+	}
+	if err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
 	if msg == "" {
 		return cli.Exit("Message is empty", codes.ParamsError)
 	}
 
-	client := metadata.GetClient(c.App.Metadata)
-	renderer := metadata.GetRenderer(c.App.Metadata)
 	sendFlags, err := flags.NewSendFlags(c)
 	if err != nil {
 		return cli.Exit(err.Error(), codes.ParamsError)
 	}
 
+	normalizer, err := flags.NewPhoneNormalizer(c)
+	if err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+	phoneNumbers, err := normalizePhones(c.StringSlice("phones"), normalizer, sendFlags.SkipPhoneValidation)
+	if err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+
+	client := metadata.GetClient(c.App.Metadata)
+	renderer := metadata.GetRenderer(c.App.Metadata)
+	if c.Bool("wait") && c.String("until") == untilDelivered && !sendFlags.DeliveryReport {
+		return cli.Exit("Waiting until delivered requires delivery reports, use --until sent", codes.ParamsError)
+	}
+
 	isDataMessage := c.Bool("data")
 	var dataMessage *smsgateway.DataMessage
 	var textMessage *smsgateway.TextMessage
 	if isDataMessage {
+		port, _ := flags.DataPort(c)
 		dataMessage = &smsgateway.DataMessage{
 			Data: msg,
-			Port: uint16(c.Uint("data-port")), //nolint:gosec // validated
+			Port: port,
 		}
 	} else {
 		textMessage = &smsgateway.TextMessage{
@@ -117,7 +200,7 @@ func sendAction(c *cli.Context) error {
 		Message:      "",
 		TextMessage:  textMessage,
 		DataMessage:  dataMessage,
-		PhoneNumbers: c.StringSlice("phones"),
+		PhoneNumbers: phoneNumbers,
 		IsEncrypted:  false,
 
 		DeviceID:           "",
@@ -130,18 +213,75 @@ func sendAction(c *cli.Context) error {
 	}
 	req = sendFlags.Merge(req)
 
+	if encryptor := flags.NewEncryptor(c); encryptor != nil {
+		if req, err = encryptor.EncryptMessage(req); err != nil {
+			return cli.Exit(err.Error(), codes.InternalError)
+		}
+	}
+
 	options := sendFlags.Option()
 
-	res, err := client.Send(c.Context, req, options...)
+	res, err := sendWithRetries(c, client, req, options)
 	if err != nil {
 		return cli.Exit(err.Error(), codes.ClientError)
 	}
 
-	s, err := renderer.MessageState(res)
-	if err != nil {
-		return cli.Exit(err.Error(), codes.OutputError)
+	if printErr := printStates(c, renderer, []smsgateway.MessageState{res}, false); printErr != nil {
+		return printErr
+	}
+
+	if c.Bool("wait") {
+		return waitAction(c, client, renderer, res)
 	}
-	fmt.Fprintln(os.Stdout, s)
 
 	return nil
 }
+
+// normalizePhones converts the recipients to E.164, reporting all invalid numbers
+// at once. The numbers are sent as is when phone validation is skipped.
+func normalizePhones(numbers []string, normalizer *phones.Normalizer, skipValidation bool) ([]string, error) {
+	if skipValidation {
+		return numbers, nil
+	}
+
+	normalized := make([]string, 0, len(numbers))
+	errs := make([]string, 0)
+	for _, number := range numbers {
+		phone, err := normalizer.Normalize(number)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		normalized = append(normalized, phone)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %s", flags.ErrValidationFailed, strings.Join(errs, "; "))
+	}
+
+	return normalized, nil
+}
+
+// sendWithRetries sends the message with the retry policy of the flags,
+// reporting the number of attempts on stderr when retries are enabled. A message
+// without an ID gets one before the first attempt, so the server recognizes a
+// repeated request instead of enqueuing the message twice.
+func sendWithRetries(
+	c *cli.Context,
+	client *smsgateway.Client,
+	req smsgateway.Message,
+	options []smsgateway.SendOption,
+) (smsgateway.MessageState, error) {
+	if req.ID == "" {
+		req.ID = uuid.Must(uuid.NewV7()).String()
+	}
+
+	policy, _ := flags.NewRetryPolicy(c)
+	ctx, counter := retry.WithPolicy(c.Context, policy)
+
+	res, err := client.Send(ctx, req, options...)
+	if policy.Retries > 0 {
+		fmt.Fprintf(os.Stderr, "Attempts: %d\n", counter.Attempts())
+	}
+
+	return res, err //nolint:wrapcheck // the client error is shown as is
+}
@@ -1,43 +1,321 @@
 package messages
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
+	"github.com/android-sms-gateway/cli/internal/commands/flags"
 	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/core/output"
 	"github.com/android-sms-gateway/cli/internal/utils/metadata"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/samber/lo"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
 )
 
+// clearScreen moves the cursor home and clears the terminal before redrawing the live table.
+const clearScreen = "\033[H\033[2J"
+
 func statusCmd() *cli.Command {
 	return &cli.Command{
 		Name:      "status",
 		Aliases:   []string{"state"},
 		Usage:     "Get message status",
 		Args:      true,
-		ArgsUsage: "Message ID",
+		ArgsUsage: "Message ID... ('-' to read IDs from stdin)",
 		Category:  "Messages",
-		Action: func(c *cli.Context) error {
-			id := c.Args().Get(0)
-			if id == "" {
-				return cli.Exit("Message ID is empty", codes.ParamsError)
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:     "ids-file",
+				Usage:    "Read message IDs from file, one per line ('-' for stdin)",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "watch",
+				Aliases:  []string{"w"},
+				Category: "Watch",
+				Usage:    "Poll until every message reaches the final state, redrawing a live table",
+				Value:    false,
+			},
+			&cli.DurationFlag{
+				Name:     "watch-timeout",
+				Category: "Watch",
+				Usage:    "Maximum time to watch the messages (0 for no limit)",
+				Value:    defaultWaitTimeout,
+			},
+			&cli.StringFlag{
+				Name:     "until",
+				Category: "Watch",
+				Usage:    "Target state to wait for: sent or delivered",
+				Value:    untilDelivered,
+			},
+		}, flags.Encryption()...),
+		Before: func(c *cli.Context) error {
+			if until := c.String("until"); until != untilSent && until != untilDelivered {
+				return cli.Exit("Until must be one of: sent, delivered", codes.ParamsError)
+			}
+			if c.Duration("watch-timeout") < 0 {
+				return cli.Exit("Watch timeout must not be negative", codes.ParamsError)
 			}
 
-			client := metadata.GetClient(c.App.Metadata)
-			renderer := metadata.GetRenderer(c.App.Metadata)
+			return nil
+		},
+		Action: statusAction,
+	}
+}
 
-			res, err := client.GetState(c.Context, id)
-			if err != nil {
-				return cli.Exit(err.Error(), codes.ClientError)
-			}
+func statusAction(c *cli.Context) error {
+	ids, list, err := messageIDs(c)
+	if err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+	if len(ids) == 0 {
+		return cli.Exit("Message ID is empty", codes.ParamsError)
+	}
 
-			s, err := renderer.MessageState(res)
-			if err != nil {
-				return cli.Exit(err.Error(), codes.OutputError)
-			}
-			fmt.Fprintln(os.Stdout, s)
+	client := metadata.GetClient(c.App.Metadata)
+	renderer := metadata.GetRenderer(c.App.Metadata)
+
+	states := make([]smsgateway.MessageState, 0, len(ids))
+	for _, id := range ids {
+		res, stateErr := client.GetState(c.Context, id)
+		if stateErr != nil {
+			return cli.Exit(stateErr.Error(), codes.ClientError)
+		}
+		states = append(states, res)
+	}
+
+	if c.Bool("watch") {
+		return watchAction(c, client, renderer, states, list)
+	}
+
+	return printStates(c, renderer, states, list)
+}
+
+// messageIDs collects message IDs from the arguments and the --ids-file flag.
+// Duplicates are removed while keeping the original order. The returned flag
+// reports whether the IDs are a list, read from a file, stdin or several
+// arguments, which is rendered as a list even when it holds a single ID.
+func messageIDs(c *cli.Context) ([]string, bool, error) {
+	args := c.Args().Slice()
+	path := c.String("ids-file")
+
+	stdinReads := lo.Count(args, stdinSource)
+	if path == stdinSource {
+		stdinReads++
+	}
+	if stdinReads > 1 {
+		return nil, false, fmt.Errorf("%w: pass '-' either once as an argument or to --ids-file", ErrStdinReused)
+	}
+
+	var ids []string
+	for _, arg := range args {
+		if arg != stdinSource {
+			ids = append(ids, arg)
+			continue
+		}
+
+		fromStdin, err := readIDs(os.Stdin)
+		if err != nil {
+			return nil, false, err
+		}
+		ids = append(ids, fromStdin...)
+	}
+
+	if path != "" {
+		fromFile, err := readIDsFile(path)
+		if err != nil {
+			return nil, false, err
+		}
+		ids = append(ids, fromFile...)
+	}
+
+	seen := make(map[string]struct{}, len(ids))
+	unique := ids[:0]
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	return unique, len(args) > 1 || stdinReads > 0 || path != "", nil
+}
+
+func readIDsFile(path string) ([]string, error) {
+	if path == stdinSource {
+		return readIDs(os.Stdin)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open IDs file: %w", err)
+	}
+	defer f.Close()
+
+	return readIDs(f)
+}
+
+// readIDs reads one message ID per line, skipping blank lines and # comments.
+func readIDs(r io.Reader) ([]string, error) {
+	var ids []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read message IDs: %w", err)
+	}
+
+	return ids, nil
+}
+
+// printStates renders a single state or the list of states, decrypting them if needed.
+// With list set the states are rendered as a list even when there is only one.
+func printStates(c *cli.Context, renderer output.Renderer, states []smsgateway.MessageState, list bool) error {
+	states, err := decryptStates(c, states)
+	if err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+
+	var s string
+	if len(states) == 1 && !list {
+		s, err = renderer.MessageState(states[0])
+	} else {
+		s, err = renderer.MessageStates(states)
+	}
+	if err != nil {
+		return cli.Exit(err.Error(), codes.OutputError)
+	}
+	fmt.Fprintln(os.Stdout, s)
+
+	return nil
+}
 
+// watchAction polls the messages until every one reaches the final state.
+// When stdout is a terminal the state table is redrawn on every change;
+// the final states are printed with the selected renderer followed by a summary on stderr.
+func watchAction(
+	c *cli.Context,
+	client *smsgateway.Client,
+	renderer output.Renderer,
+	states []smsgateway.MessageState,
+	list bool,
+) error {
+	ctx := c.Context
+	if timeout := c.Duration("watch-timeout"); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	live := term.IsTerminal(int(os.Stdout.Fd()))
+	table := output.NewTableOutput()
+	redraw := func(next []smsgateway.MessageState) error {
+		if !live {
 			return nil
-		},
+		}
+
+		decrypted, decErr := decryptStates(c, next)
+		if decErr != nil {
+			return cli.Exit(decErr.Error(), codes.ParamsError)
+		}
+
+		s, renderErr := table.MessageStates(decrypted)
+		if renderErr != nil {
+			return cli.Exit(renderErr.Error(), codes.OutputError)
+		}
+		fmt.Fprint(os.Stdout, clearScreen)
+		fmt.Fprintln(os.Stdout, s)
+		return nil
+	}
+
+	if drawErr := redraw(states); drawErr != nil {
+		return drawErr
+	}
+
+	final, err := pollStates(ctx, client, states, c.String("until"), redraw)
+
+	var exitErr cli.ExitCoder
+	if errors.As(err, &exitErr) {
+		return err
+	}
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return cli.Exit(err.Error(), codes.ClientError)
+	}
+
+	if live {
+		fmt.Fprint(os.Stdout, clearScreen)
+	}
+	if printErr := printStates(c, renderer, final, list); printErr != nil {
+		return printErr
 	}
+
+	summary := summarize(final)
+	fmt.Fprintf(
+		os.Stderr,
+		"Summary: %d message(s), %d delivered, %d sent, %d failed, %d pending\n",
+		len(final), summary.delivered, summary.sent, summary.failed, summary.pending,
+	)
+
+	switch {
+	case err != nil:
+		return cli.Exit("Timeout waiting for messages to reach the final state", codes.Timeout)
+	case summary.failed > 0:
+		return cli.Exit(fmt.Sprintf("%d recipient(s) failed", summary.failed), codes.DeliveryFailed)
+	}
+
+	return nil
+}
+
+type stateSummary struct {
+	delivered int
+	sent      int
+	failed    int
+	pending   int
+}
+
+// summarize counts recipients by state; messages without recipients are counted by the message state.
+func summarize(states []smsgateway.MessageState) stateSummary {
+	summary := stateSummary{}
+
+	count := func(state smsgateway.ProcessingState) {
+		switch state {
+		case smsgateway.ProcessingStateDelivered:
+			summary.delivered++
+		case smsgateway.ProcessingStateSent:
+			summary.sent++
+		case smsgateway.ProcessingStateFailed:
+			summary.failed++
+		case smsgateway.ProcessingStatePending, smsgateway.ProcessingStateProcessed:
+			summary.pending++
+		default:
+			summary.pending++
+		}
+	}
+
+	for _, m := range states {
+		if len(m.Recipients) == 0 {
+			count(m.State)
+			continue
+		}
+
+		for _, r := range m.Recipients {
+			count(r.State)
+		}
+	}
+
+	return summary
 }
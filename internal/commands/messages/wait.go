@@ -0,0 +1,185 @@
+package messages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/core/output"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	untilSent      = "sent"
+	untilDelivered = "delivered"
+
+	// defaultWaitTimeout limits waiting for messages that never reach the target
+	// state, e.g. messages sent without delivery reports stay Sent.
+	defaultWaitTimeout = 5 * time.Minute
+
+	pollInitialInterval = time.Second
+	pollMaxInterval     = 30 * time.Second
+	pollBackoffFactor   = 2
+)
+
+// isFinalState reports whether the processing state will not change anymore
+// with respect to the target state.
+func isFinalState(state smsgateway.ProcessingState, until string) bool {
+	switch state {
+	case smsgateway.ProcessingStateDelivered, smsgateway.ProcessingStateFailed:
+		return true
+	case smsgateway.ProcessingStateSent:
+		return until == untilSent
+	case smsgateway.ProcessingStatePending, smsgateway.ProcessingStateProcessed:
+		return false
+	default:
+		return false
+	}
+}
+
+// isComplete reports whether every recipient of the message reached a final state.
+func isComplete(state smsgateway.MessageState, until string) bool {
+	if len(state.Recipients) == 0 {
+		return isFinalState(state.State, until)
+	}
+
+	for _, r := range state.Recipients {
+		if !isFinalState(r.State, until) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// allComplete reports whether every message reached a final state.
+func allComplete(states []smsgateway.MessageState, until string) bool {
+	return !slices.ContainsFunc(states, func(s smsgateway.MessageState) bool {
+		return !isComplete(s, until)
+	})
+}
+
+// hasFailed reports whether the message or any of its recipients failed.
+func hasFailed(state smsgateway.MessageState) bool {
+	if state.State == smsgateway.ProcessingStateFailed {
+		return true
+	}
+
+	for _, r := range state.Recipients {
+		if r.State == smsgateway.ProcessingStateFailed {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isStateChanged(prev, next smsgateway.MessageState) bool {
+	if prev.State != next.State || len(prev.Recipients) != len(next.Recipients) {
+		return true
+	}
+
+	for i := range prev.Recipients {
+		if prev.Recipients[i].State != next.Recipients[i].State {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pollStates polls the messages state with exponential backoff until every message
+// is complete for the target state. onChange is called after each round with state transitions.
+func pollStates(
+	ctx context.Context,
+	client *smsgateway.Client,
+	states []smsgateway.MessageState,
+	until string,
+	onChange func([]smsgateway.MessageState) error,
+) ([]smsgateway.MessageState, error) {
+	states = slices.Clone(states)
+
+	if allComplete(states, until) {
+		return states, nil
+	}
+
+	interval := pollInitialInterval
+
+	for {
+		select {
+		case <-ctx.Done():
+			return states, fmt.Errorf("wait for messages: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+
+		changed := false
+		for i, state := range states {
+			if isComplete(state, until) {
+				continue
+			}
+
+			next, err := client.GetState(ctx, state.ID)
+			if err != nil {
+				return states, fmt.Errorf("get state of message %s: %w", state.ID, err)
+			}
+
+			changed = changed || isStateChanged(state, next)
+			states[i] = next
+		}
+
+		if changed {
+			if cbErr := onChange(states); cbErr != nil {
+				return states, cbErr
+			}
+		}
+
+		if allComplete(states, until) {
+			return states, nil
+		}
+
+		interval = min(interval*pollBackoffFactor, pollMaxInterval)
+	}
+}
+
+// waitAction waits for the sent message to reach the target state, printing
+// every transition, and maps the final outcome to the exit code.
+func waitAction(
+	c *cli.Context,
+	client *smsgateway.Client,
+	renderer output.Renderer,
+	state smsgateway.MessageState,
+) error {
+	ctx, cancel := context.WithTimeout(c.Context, c.Duration("wait-timeout"))
+	defer cancel()
+
+	states, err := pollStates(
+		ctx,
+		client,
+		[]smsgateway.MessageState{state},
+		c.String("until"),
+		func(next []smsgateway.MessageState) error {
+			return printStates(c, renderer, next, false)
+		},
+	)
+	final := states[0]
+
+	var exitErr cli.ExitCoder
+	switch {
+	case errors.As(err, &exitErr):
+		return err
+	case errors.Is(err, context.DeadlineExceeded):
+		return cli.Exit(fmt.Sprintf("Timeout waiting for message %s, last state: %s", final.ID, final.State), codes.Timeout)
+	case err != nil:
+		return cli.Exit(err.Error(), codes.ClientError)
+	}
+
+	if hasFailed(final) {
+		return cli.Exit(fmt.Sprintf("Message %s failed for one or more recipients", final.ID), codes.DeliveryFailed)
+	}
+
+	return nil
+}
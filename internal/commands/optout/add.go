@@ -0,0 +1,60 @@
+package optout
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/android-sms-gateway/cli/internal/commands/flags"
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/optout"
+	"github.com/urfave/cli/v2"
+)
+
+func addCmd() *cli.Command {
+	fl := []cli.Flag{reasonFlag()}
+	fl = append(fl, flags.Phone()...)
+
+	return &cli.Command{
+		Category:  categoryMessages,
+		Name:      "add",
+		Usage:     "Add phone numbers to the opt-out list",
+		Args:      true,
+		ArgsUsage: "PHONE...",
+		Flags:     fl,
+		Action: func(c *cli.Context) error {
+			if c.Args().Len() == 0 {
+				return cli.Exit("phone numbers are required", codes.ParamsError)
+			}
+
+			phones, err := normalize(c, c.Args().Slice())
+			if err != nil {
+				return err
+			}
+
+			store, err := newStore(c)
+			if err != nil {
+				return err
+			}
+
+			added, err := store.Add(newEntries(phones, c.String("reason")))
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to save opt-out list: %s", err.Error()), codes.OutputError)
+			}
+			fmt.Fprintf(os.Stderr, "Added %d numbers, %d already opted out\n", added, len(phones)-added)
+
+			return printSuccess(c)
+		},
+	}
+}
+
+// newEntries returns the opt-out entries of the phone numbers added now.
+func newEntries(phones []string, reason string) []optout.Entry {
+	now := time.Now().UTC()
+	entries := make([]optout.Entry, 0, len(phones))
+	for _, phone := range phones {
+		entries = append(entries, optout.Entry{Phone: phone, Reason: reason, Added: now})
+	}
+
+	return entries
+}
@@ -0,0 +1,60 @@
+package optout
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/android-sms-gateway/cli/internal/commands/flags"
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/optout"
+	"github.com/urfave/cli/v2"
+)
+
+func importCmd() *cli.Command {
+	fl := []cli.Flag{
+		&cli.StringFlag{
+			Name:  "column",
+			Usage: "Header of the phone number column, a single-column file may have no header",
+			Value: "phone",
+		},
+		reasonFlag(),
+	}
+	fl = append(fl, flags.Phone()...)
+
+	return &cli.Command{
+		Category:  categoryMessages,
+		Name:      "import",
+		Usage:     "Add the phone numbers of a CSV file to the opt-out list",
+		Args:      true,
+		ArgsUsage: "filename.csv",
+		Flags:     fl,
+		Action: func(c *cli.Context) error {
+			if c.Args().Len() != 1 {
+				return cli.Exit("filename is required", codes.ParamsError)
+			}
+
+			numbers, err := optout.ReadCSV(c.Context, c.Args().First(), c.String("column"))
+			if err != nil {
+				return cli.Exit(err.Error(), codes.ParamsError)
+			}
+
+			phones, err := normalize(c, numbers)
+			if err != nil {
+				return err
+			}
+
+			store, err := newStore(c)
+			if err != nil {
+				return err
+			}
+
+			added, err := store.Add(newEntries(phones, c.String("reason")))
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to save opt-out list: %s", err.Error()), codes.OutputError)
+			}
+			fmt.Fprintf(os.Stderr, "Imported %d numbers, %d already opted out\n", added, len(phones)-added)
+
+			return printSuccess(c)
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package optout
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/core/output"
+	"github.com/urfave/cli/v2"
+)
+
+func listCmd() *cli.Command {
+	return &cli.Command{
+		Category: categoryMessages,
+		Name:     "list",
+		Aliases:  []string{"l", "ls"},
+		Usage:    "List opted-out phone numbers with the time they were added and the reason",
+		Action: func(c *cli.Context) error {
+			store, err := newStore(c)
+			if err != nil {
+				return err
+			}
+
+			entries, err := store.List()
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to read opt-out list: %s", err.Error()), codes.ParamsError)
+			}
+
+			if len(entries) == 0 {
+				fmt.Fprintln(os.Stdout, output.EmptyResult)
+				return nil
+			}
+
+			for _, entry := range entries {
+				fmt.Fprintf(os.Stdout, "%s\t%s\t%s\n", entry.Phone, entry.Added.Format(time.RFC3339), entry.Reason)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,99 @@
+package optout
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/android-sms-gateway/cli/internal/commands/flags"
+	"github.com/android-sms-gateway/cli/internal/config"
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/optout"
+	"github.com/android-sms-gateway/cli/internal/phones"
+	"github.com/android-sms-gateway/cli/internal/utils/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	categoryMessages = "Messages"
+)
+
+func Commands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Category: categoryMessages,
+			Name:     "optout",
+			Usage:    "Manage the local list of phone numbers excluded from batch sends",
+			Subcommands: []*cli.Command{
+				addCmd(),
+				removeCmd(),
+				listCmd(),
+				importCmd(),
+			},
+		},
+	}
+}
+
+// newStore opens the opt-out store located next to the config file.
+func newStore(c *cli.Context) (*optout.Store, error) {
+	path, err := config.ResolvePath(c.String("config"))
+	if err != nil {
+		return nil, cli.Exit(err.Error(), codes.ParamsError)
+	}
+
+	return optout.NewStore(filepath.Dir(path)), nil
+}
+
+// normalize converts the numbers to E.164, reporting all invalid numbers at once.
+// The list is matched by E.164 numbers, so national numbers require a default region.
+func normalize(c *cli.Context, numbers []string) ([]string, error) {
+	normalizer, err := flags.NewPhoneNormalizer(c)
+	if err != nil {
+		return nil, cli.Exit(err.Error(), codes.ParamsError)
+	}
+
+	result := make([]string, 0, len(numbers))
+	errs := make([]string, 0)
+	for _, number := range numbers {
+		phone, normErr := normalizer.Normalize(number)
+		if normErr == nil && !strings.HasPrefix(phone, "+") {
+			normErr = fmt.Errorf(
+				"%w %q: not in the international format, add the country code or set --default-region",
+				phones.ErrInvalidNumber,
+				number,
+			)
+		}
+		if normErr != nil {
+			errs = append(errs, normErr.Error())
+			continue
+		}
+		result = append(result, phone)
+	}
+	if len(errs) > 0 {
+		return nil, cli.Exit(strings.Join(errs, "\n"), codes.ParamsError)
+	}
+	return result, nil
+}
+
+// reasonFlag is the flag recording why the numbers opted out.
+func reasonFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "reason",
+		Usage: "Why the numbers opted out, e.g. STOP reply",
+		Value: "",
+	}
+}
+
+// printSuccess writes the success result to stdout.
+func printSuccess(c *cli.Context) error {
+	b, err := metadata.GetRenderer(c.App.Metadata).Success()
+	if err != nil {
+		return cli.Exit(err.Error(), codes.OutputError)
+	}
+	if b != "" {
+		fmt.Fprintln(os.Stdout, b)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package optout
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/android-sms-gateway/cli/internal/commands/flags"
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/urfave/cli/v2"
+)
+
+func removeCmd() *cli.Command {
+	return &cli.Command{
+		Category:  categoryMessages,
+		Name:      "remove",
+		Aliases:   []string{"rm", "delete"},
+		Usage:     "Remove phone numbers from the opt-out list",
+		Args:      true,
+		ArgsUsage: "PHONE...",
+		Flags:     flags.Phone(),
+		Action: func(c *cli.Context) error {
+			if c.Args().Len() == 0 {
+				return cli.Exit("phone numbers are required", codes.ParamsError)
+			}
+
+			phones, err := normalize(c, c.Args().Slice())
+			if err != nil {
+				return err
+			}
+
+			store, err := newStore(c)
+			if err != nil {
+				return err
+			}
+
+			removed, err := store.Remove(phones)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to save opt-out list: %s", err.Error()), codes.OutputError)
+			}
+			if removed == 0 {
+				fmt.Fprintln(os.Stderr, "No matching numbers found")
+			} else {
+				fmt.Fprintf(os.Stderr, "Removed %d numbers\n", removed)
+			}
+
+			return printSuccess(c)
+		},
+	}
+}
@@ -0,0 +1,40 @@
+package profiles
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/android-sms-gateway/cli/internal/core/output"
+	"github.com/urfave/cli/v2"
+)
+
+func listCmd() *cli.Command {
+	return &cli.Command{
+		Category: categoryConfig,
+		Name:     "list",
+		Aliases:  []string{"l", "ls"},
+		Usage:    "List profiles, the current one is marked with *",
+		Action: func(c *cli.Context) error {
+			_, file, err := load(c)
+			if err != nil {
+				return err
+			}
+
+			names := file.Names()
+			if len(names) == 0 {
+				fmt.Fprintln(os.Stdout, output.EmptyResult)
+				return nil
+			}
+
+			for _, name := range names {
+				marker := " "
+				if name == file.Current {
+					marker = "*"
+				}
+				fmt.Fprintf(os.Stdout, "%s %s\n", marker, name)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,53 @@
+package profiles
+
+import (
+	"fmt"
+
+	"github.com/android-sms-gateway/cli/internal/config"
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	categoryConfig = "Configuration"
+)
+
+func Commands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Category: categoryConfig,
+			Name:     "config",
+			Aliases:  []string{"profiles"},
+			Usage:    "Manage connection profiles",
+			Subcommands: []*cli.Command{
+				listCmd(),
+				showCmd(),
+				setCmd(),
+				useCmd(),
+				templateCmd(),
+			},
+		},
+	}
+}
+
+func load(c *cli.Context) (string, *config.File, error) {
+	path, err := config.ResolvePath(c.String("config"))
+	if err != nil {
+		return "", nil, cli.Exit(err.Error(), codes.ParamsError)
+	}
+
+	file, err := config.Load(path)
+	if err != nil {
+		return "", nil, cli.Exit(err.Error(), codes.ParamsError)
+	}
+
+	return path, file, nil
+}
+
+func save(path string, file *config.File) error {
+	if err := file.Save(path); err != nil {
+		return cli.Exit(fmt.Sprintf("failed to save config: %s", err.Error()), codes.OutputError)
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package profiles
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/core/output"
+	"github.com/samber/lo"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	categoryConnection = "Connection"
+	categorySend       = "Send defaults"
+)
+
+func setCmd() *cli.Command {
+	return &cli.Command{
+		Category:  categoryConfig,
+		Name:      "set",
+		Usage:     "Create a profile or update its settings; only provided options are changed",
+		Args:      true,
+		ArgsUsage: "name",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "endpoint",
+				Category: categoryConnection,
+				Usage:    "Endpoint",
+			},
+			&cli.StringFlag{
+				Name:     "username",
+				Category: categoryConnection,
+				Usage:    "Username",
+			},
+			&cli.StringFlag{
+				Name:     "password",
+				Category: categoryConnection,
+				Usage:    "Password",
+			},
+			&cli.StringFlag{
+				Name:     "token",
+				Category: categoryConnection,
+				Usage:    "JWT access token, replaces username and password",
+			},
+			&cli.StringFlag{
+				Name:     "format",
+				Category: categoryConnection,
+				Usage:    "Default output format. Supported: text, json, raw, table, csv",
+			},
+
+			&cli.StringFlag{
+				Name:     "device-id",
+				Category: categorySend,
+				Usage:    "Default device ID",
+			},
+			&cli.UintFlag{
+				Name:     "sim-number",
+				Category: categorySend,
+				Usage:    "Default SIM card index (one-based index, e.g. 1)",
+			},
+			&cli.BoolFlag{
+				Name:     "delivery-report",
+				Category: categorySend,
+				Usage:    "Default delivery report setting",
+			},
+			&cli.IntFlag{
+				Name:     "priority",
+				Category: categorySend,
+				Usage:    "Default priority (-128 to 127)",
+			},
+			&cli.DurationFlag{
+				Name:     "ttl",
+				Category: categorySend,
+				Usage:    "Default time to live (duration, e.g. 1h30m)",
+			},
+			&cli.BoolFlag{
+				Name:     "skip-phone-validation",
+				Category: categorySend,
+				Usage:    "Default phone number validation setting",
+			},
+			&cli.UintFlag{
+				Name:     "device-active-within",
+				Category: categorySend,
+				Usage:    "Default device activity filter in hours",
+			},
+		},
+		Action: setAction,
+	}
+}
+
+func setAction(c *cli.Context) error {
+	name := c.Args().Get(0)
+	if name == "" {
+		return cli.Exit("Profile name is empty", codes.ParamsError)
+	}
+	if c.Args().Len() > 1 {
+		return cli.Exit("Unexpected arguments, options must precede the profile name", codes.ParamsError)
+	}
+
+	if c.IsSet("format") {
+		if _, err := output.New(output.Format(c.String("format"))); err != nil {
+			return cli.Exit(fmt.Sprintf("invalid format %q: %s", c.String("format"), err.Error()), codes.ParamsError)
+		}
+	}
+
+	path, file, err := load(c)
+	if err != nil {
+		return err
+	}
+
+	profile := file.Profiles[name]
+
+	if c.IsSet("endpoint") {
+		profile.Endpoint = c.String("endpoint")
+	}
+	if c.IsSet("username") {
+		profile.Username = c.String("username")
+	}
+	if c.IsSet("password") {
+		profile.Password = c.String("password")
+	}
+	if c.IsSet("token") {
+		profile.Token = c.String("token")
+	}
+	if c.IsSet("format") {
+		profile.Format = c.String("format")
+	}
+
+	if c.IsSet("device-id") {
+		profile.Send.DeviceID = c.String("device-id")
+	}
+	if c.IsSet("sim-number") {
+		profile.Send.SimNumber = lo.ToPtr(c.Uint("sim-number"))
+	}
+	if c.IsSet("delivery-report") {
+		profile.Send.DeliveryReport = lo.ToPtr(c.Bool("delivery-report"))
+	}
+	if c.IsSet("priority") {
+		profile.Send.Priority = lo.ToPtr(c.Int("priority"))
+	}
+	if c.IsSet("ttl") {
+		profile.Send.TTL = lo.ToPtr(c.Duration("ttl"))
+	}
+	if c.IsSet("skip-phone-validation") {
+		profile.Send.SkipPhoneValidation = lo.ToPtr(c.Bool("skip-phone-validation"))
+	}
+	if c.IsSet("device-active-within") {
+		profile.Send.DeviceActiveWithin = lo.ToPtr(c.Uint("device-active-within"))
+	}
+
+	file.Profiles[name] = profile
+	if saveErr := save(path, file); saveErr != nil {
+		return saveErr
+	}
+
+	fmt.Fprintf(os.Stdout, "Profile %q saved to %s\n", name, path)
+
+	return nil
+}
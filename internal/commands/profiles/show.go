@@ -0,0 +1,57 @@
+package profiles
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const secretMask = "********"
+
+func showCmd() *cli.Command {
+	return &cli.Command{
+		Category:  categoryConfig,
+		Name:      "show",
+		Usage:     "Show profile settings, secrets are masked",
+		Args:      true,
+		ArgsUsage: "[name] (defaults to the current profile)",
+		Action: func(c *cli.Context) error {
+			_, file, err := load(c)
+			if err != nil {
+				return err
+			}
+
+			name := c.Args().Get(0)
+			if name == "" {
+				name = file.Current
+			}
+			if name == "" {
+				return cli.Exit("Profile name is empty and no current profile is selected", codes.ParamsError)
+			}
+
+			profile, err := file.Profile(name)
+			if err != nil {
+				return cli.Exit(err.Error(), codes.ParamsError)
+			}
+			if profile.Password != "" {
+				profile.Password = secretMask
+			}
+			if profile.Token != "" {
+				profile.Token = secretMask
+			}
+
+			b, err := yaml.Marshal(profile)
+			if err != nil {
+				return cli.Exit(err.Error(), codes.OutputError)
+			}
+
+			fmt.Fprintf(os.Stdout, "name: %s\n%s\n", name, strings.TrimRight(string(b), "\n"))
+
+			return nil
+		},
+	}
+}
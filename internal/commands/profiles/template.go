@@ -0,0 +1,119 @@
+package profiles
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/android-sms-gateway/cli/internal/config"
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/templates"
+	"github.com/urfave/cli/v2"
+)
+
+func templateCmd() *cli.Command {
+	return &cli.Command{
+		Category: categoryConfig,
+		Name:     "template",
+		Aliases:  []string{"templates"},
+		Usage:    "Manage named message templates of the selected profile (see --profile)",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "set",
+				Usage:     "Create or replace a named template",
+				Args:      true,
+				ArgsUsage: "name template",
+				Action:    templateSetAction,
+			},
+			{
+				Name:      "delete",
+				Aliases:   []string{"rm"},
+				Usage:     "Delete a named template",
+				Args:      true,
+				ArgsUsage: "name",
+				Action:    templateDeleteAction,
+			},
+		},
+	}
+}
+
+func templateSetAction(c *cli.Context) error {
+	const argsCount = 2
+
+	if c.Args().Len() != argsCount {
+		return cli.Exit("Template name and text are required", codes.ParamsError)
+	}
+
+	name, text := c.Args().Get(0), c.Args().Get(1)
+	if _, err := templates.Parse(text); err != nil {
+		return cli.Exit(err.Error(), codes.ParamsError)
+	}
+
+	path, file, profileName, err := loadSelected(c)
+	if err != nil {
+		return err
+	}
+
+	profile := file.Profiles[profileName]
+	if profile.Templates == nil {
+		profile.Templates = map[string]string{}
+	}
+	profile.Templates[name] = text
+	file.Profiles[profileName] = profile
+
+	if saveErr := save(path, file); saveErr != nil {
+		return saveErr
+	}
+
+	fmt.Fprintf(os.Stdout, "Template %q saved to profile %q\n", name, profileName)
+
+	return nil
+}
+
+func templateDeleteAction(c *cli.Context) error {
+	name := c.Args().Get(0)
+	if name == "" {
+		return cli.Exit("Template name is empty", codes.ParamsError)
+	}
+
+	path, file, profileName, err := loadSelected(c)
+	if err != nil {
+		return err
+	}
+
+	profile := file.Profiles[profileName]
+	if _, ok := profile.Templates[name]; !ok {
+		return cli.Exit(fmt.Sprintf("Template %q not found in profile %q", name, profileName), codes.ParamsError)
+	}
+	delete(profile.Templates, name)
+	file.Profiles[profileName] = profile
+
+	if saveErr := save(path, file); saveErr != nil {
+		return saveErr
+	}
+
+	fmt.Fprintf(os.Stdout, "Template %q deleted from profile %q\n", name, profileName)
+
+	return nil
+}
+
+// loadSelected loads the config and resolves the profile selected by --profile
+// or the current one.
+func loadSelected(c *cli.Context) (string, *config.File, string, error) {
+	path, file, err := load(c)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	name := c.String("profile")
+	if name == "" {
+		name = file.Current
+	}
+	if name == "" {
+		return "", nil, "", cli.Exit("No profile selected, use --profile or `config use`", codes.ParamsError)
+	}
+	if _, ok := file.Profiles[name]; !ok {
+		return "", nil, "", cli.Exit(fmt.Sprintf("Profile %q not found", name), codes.ParamsError)
+	}
+
+	return path, file, name, nil
+}
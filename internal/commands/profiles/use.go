@@ -0,0 +1,43 @@
+package profiles
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/urfave/cli/v2"
+)
+
+func useCmd() *cli.Command {
+	return &cli.Command{
+		Category:  categoryConfig,
+		Name:      "use",
+		Usage:     "Select the current profile",
+		Args:      true,
+		ArgsUsage: "name",
+		Action: func(c *cli.Context) error {
+			name := c.Args().Get(0)
+			if name == "" {
+				return cli.Exit("Profile name is empty", codes.ParamsError)
+			}
+
+			path, file, err := load(c)
+			if err != nil {
+				return err
+			}
+
+			if _, ok := file.Profiles[name]; !ok {
+				return cli.Exit(fmt.Sprintf("Profile %q not found", name), codes.ParamsError)
+			}
+
+			file.Current = name
+			if saveErr := save(path, file); saveErr != nil {
+				return saveErr
+			}
+
+			fmt.Fprintf(os.Stdout, "Switched to profile %q\n", name)
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,5 @@
+package settings
+
+const (
+	categorySettings = "Settings"
+)
@@ -0,0 +1,46 @@
+package settings
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/android-sms-gateway/cli/internal/utils/flatten"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+// diffSettings returns the changes between the current and the desired settings,
+// one line per key: "~ key: old -> new", "+ key: new" or "- key: old".
+// A patch only changes the provided keys, a replacement also removes omitted ones.
+func diffSettings(current, desired smsgateway.DeviceSettings, replace bool) ([]string, error) {
+	from, err := flatten.Flatten(current)
+	if err != nil {
+		return nil, fmt.Errorf("flatten current settings: %w", err)
+	}
+	to, err := flatten.Flatten(desired)
+	if err != nil {
+		return nil, fmt.Errorf("flatten desired settings: %w", err)
+	}
+
+	keys := maps.Clone(to)
+	if replace {
+		maps.Copy(keys, from)
+	}
+
+	lines := []string{}
+	for _, key := range slices.Sorted(maps.Keys(keys)) {
+		oldValue, hadOld := from[key]
+		newValue, hasNew := to[key]
+
+		switch {
+		case hadOld && hasNew && oldValue != newValue:
+			lines = append(lines, fmt.Sprintf("~ %s: %s -> %s", key, oldValue, newValue))
+		case !hadOld && hasNew:
+			lines = append(lines, fmt.Sprintf("+ %s: %s", key, newValue))
+		case hadOld && !hasNew:
+			lines = append(lines, fmt.Sprintf("- %s: %s", key, oldValue))
+		}
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,7 @@
+package settings
+
+import "errors"
+
+var (
+	ErrInvalidSettings = errors.New("invalid settings file")
+)
@@ -0,0 +1,55 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"gopkg.in/yaml.v3"
+)
+
+const stdinSource = "-"
+
+// readSettings reads settings from a JSON or YAML file. Unknown keys are rejected
+// to catch typos before anything is sent to the server.
+func readSettings(path string) (smsgateway.DeviceSettings, error) {
+	var (
+		b   []byte
+		err error
+	)
+	if path == stdinSource {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return smsgateway.DeviceSettings{}, fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	// JSON is valid YAML, so both formats are decoded the same way
+	var raw any
+	if unmErr := yaml.Unmarshal(b, &raw); unmErr != nil {
+		return smsgateway.DeviceSettings{}, fmt.Errorf("%w: %w", ErrInvalidSettings, unmErr)
+	}
+	if raw == nil {
+		return smsgateway.DeviceSettings{}, fmt.Errorf("%w: file is empty", ErrInvalidSettings)
+	}
+
+	j, err := json.Marshal(raw)
+	if err != nil {
+		return smsgateway.DeviceSettings{}, fmt.Errorf("%w: %w", ErrInvalidSettings, err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(j))
+	dec.DisallowUnknownFields()
+
+	settings := smsgateway.DeviceSettings{}
+	if decErr := dec.Decode(&settings); decErr != nil {
+		return smsgateway.DeviceSettings{}, fmt.Errorf("%w: %w", ErrInvalidSettings, decErr)
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,36 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/utils/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+func getCmd() *cli.Command {
+	return &cli.Command{
+		Category: categorySettings,
+		Name:     "get",
+		Aliases:  []string{"show"},
+		Usage:    "Get current settings",
+		Action: func(c *cli.Context) error {
+			client := metadata.GetClient(c.App.Metadata)
+			renderer := metadata.GetRenderer(c.App.Metadata)
+
+			res, err := client.GetSettings(c.Context)
+			if err != nil {
+				return cli.Exit(err.Error(), codes.ClientError)
+			}
+
+			s, err := renderer.Settings(res)
+			if err != nil {
+				return cli.Exit(err.Error(), codes.OutputError)
+			}
+			fmt.Fprintln(os.Stdout, s)
+
+			return nil
+		},
+	}
+}
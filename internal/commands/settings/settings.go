@@ -0,0 +1,20 @@
+package settings
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+func Commands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Category: categorySettings,
+			Name:     "settings",
+			Usage:    "Manage account settings",
+			Subcommands: []*cli.Command{
+				getCmd(),
+				updateCmd(false),
+				updateCmd(true),
+			},
+		},
+	}
+}
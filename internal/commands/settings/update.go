@@ -0,0 +1,91 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/android-sms-gateway/cli/internal/core/codes"
+	"github.com/android-sms-gateway/cli/internal/utils/metadata"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/urfave/cli/v2"
+)
+
+// updateCmd builds the patch command, which changes only the provided settings,
+// or the replace command, which resets omitted settings to their defaults.
+func updateCmd(replace bool) *cli.Command {
+	name, usage := "patch", "Update the settings provided in the file, others are kept"
+	if replace {
+		name, usage = "replace", "Replace all settings with the file contents, omitted settings are reset"
+	}
+
+	return &cli.Command{
+		Category: categorySettings,
+		Name:     name,
+		Usage:    usage,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "file",
+				Aliases:  []string{"f"},
+				Usage:    "Settings file in JSON or YAML format ('-' for stdin)",
+				Required: true,
+			},
+			&cli.BoolFlag{
+				Name:  "diff",
+				Usage: "Preview changes against the current server settings without applying them",
+				Value: false,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			desired, err := readSettings(c.String("file"))
+			if err != nil {
+				return cli.Exit(err.Error(), codes.ParamsError)
+			}
+
+			client := metadata.GetClient(c.App.Metadata)
+			renderer := metadata.GetRenderer(c.App.Metadata)
+
+			if c.Bool("diff") {
+				return diffAction(c, client, desired, replace)
+			}
+
+			var res smsgateway.DeviceSettings
+			if replace {
+				res, err = client.ReplaceSettings(c.Context, desired)
+			} else {
+				res, err = client.UpdateSettings(c.Context, desired)
+			}
+			if err != nil {
+				return cli.Exit(err.Error(), codes.ClientError)
+			}
+
+			s, err := renderer.Settings(res)
+			if err != nil {
+				return cli.Exit(err.Error(), codes.OutputError)
+			}
+			fmt.Fprintln(os.Stdout, s)
+
+			return nil
+		},
+	}
+}
+
+func diffAction(c *cli.Context, client *smsgateway.Client, desired smsgateway.DeviceSettings, replace bool) error {
+	current, err := client.GetSettings(c.Context)
+	if err != nil {
+		return cli.Exit(err.Error(), codes.ClientError)
+	}
+
+	lines, err := diffSettings(current, desired, replace)
+	if err != nil {
+		return cli.Exit(err.Error(), codes.InternalError)
+	}
+
+	if len(lines) == 0 {
+		fmt.Fprintln(os.Stdout, "No changes")
+		return nil
+	}
+	fmt.Fprintln(os.Stdout, strings.Join(lines, "\n"))
+
+	return nil
+}
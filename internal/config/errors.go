@@ -0,0 +1,8 @@
+package config
+
+import "errors"
+
+var (
+	ErrInvalidConfig   = errors.New("invalid config file")
+	ErrProfileNotFound = errors.New("profile not found")
+)
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	appDir   = "smsgate"
+	fileName = "config.yaml"
+
+	yamlIndent = 2
+)
+
+// File is the on-disk CLI configuration holding named connection profiles.
+type File struct {
+	Current  string             `yaml:"current,omitempty"`
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
+}
+
+// Profile describes a single gateway connection and its defaults.
+type Profile struct {
+	Name     string       `yaml:"-"`
+	Endpoint string       `yaml:"endpoint,omitempty"`
+	Username string       `yaml:"username,omitempty"`
+	Password string       `yaml:"password,omitempty"`
+	Token    string       `yaml:"token,omitempty"`
+	Format   string       `yaml:"format,omitempty"`
+	Send     SendDefaults `yaml:"send,omitempty"`
+
+	// Templates are named message templates available via --template-name.
+	Templates map[string]string `yaml:"templates,omitempty"`
+}
+
+// SendDefaults holds default values for the shared send flags.
+type SendDefaults struct {
+	DeviceID            string         `yaml:"device_id,omitempty"`
+	SimNumber           *uint          `yaml:"sim_number,omitempty"`
+	DeliveryReport      *bool          `yaml:"delivery_report,omitempty"`
+	Priority            *int           `yaml:"priority,omitempty"`
+	TTL                 *time.Duration `yaml:"ttl,omitempty"`
+	SkipPhoneValidation *bool          `yaml:"skip_phone_validation,omitempty"`
+	DeviceActiveWithin  *uint          `yaml:"device_active_within,omitempty"`
+}
+
+// DefaultPath returns the default location of the configuration file,
+// i.e. $XDG_CONFIG_HOME/smsgate/config.yaml or ~/.config/smsgate/config.yaml.
+func DefaultPath() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, appDir, fileName), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".config", appDir, fileName), nil
+}
+
+// ResolvePath returns path if it is not empty and DefaultPath otherwise.
+func ResolvePath(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+
+	return DefaultPath()
+}
+
+// Load reads the configuration file. A missing file yields an empty configuration.
+func Load(path string) (*File, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &File{Current: "", Profiles: map[string]Profile{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+
+	f := &File{Current: "", Profiles: map[string]Profile{}}
+	if unmErr := yaml.Unmarshal(b, f); unmErr != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidConfig, path, unmErr)
+	}
+	if f.Profiles == nil {
+		f.Profiles = map[string]Profile{}
+	}
+
+	return f, nil
+}
+
+// Save writes the configuration file readable by the current user only.
+func (f *File) Save(path string) error {
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(yamlIndent)
+	if err := enc.Encode(f); err != nil {
+		return fmt.Errorf("marshal config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("marshal config: %w", err)
+	}
+
+	if mkErr := os.MkdirAll(filepath.Dir(path), 0o700); mkErr != nil {
+		return fmt.Errorf("create config directory: %w", mkErr)
+	}
+
+	if wrErr := os.WriteFile(path, b.Bytes(), 0o600); wrErr != nil {
+		return fmt.Errorf("write config file: %w", wrErr)
+	}
+
+	return nil
+}
+
+// Profile returns the profile with the given name.
+// An empty name selects the current profile; if there is none, an empty profile is returned.
+func (f *File) Profile(name string) (Profile, error) {
+	if name == "" {
+		name = f.Current
+	}
+	if name == "" {
+		return Profile{}, nil
+	}
+
+	p, ok := f.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("%w: %q", ErrProfileNotFound, name)
+	}
+	p.Name = name
+
+	return p, nil
+}
+
+// Names returns the sorted list of profile names.
+func (f *File) Names() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
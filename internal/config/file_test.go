@@ -0,0 +1,68 @@
+package config_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/cli/internal/config"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	file, err := config.Load(filepath.Join(t.TempDir(), "config.yaml"))
+	require.NoError(t, err)
+	assert.Empty(t, file.Current)
+	assert.Empty(t, file.Profiles)
+
+	profile, err := file.Profile("")
+	require.NoError(t, err)
+	assert.Equal(t, config.Profile{}, profile)
+}
+
+func TestFile_SaveLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "smsgate", "config.yaml")
+
+	src := &config.File{
+		Current: "cloud",
+		Profiles: map[string]config.Profile{
+			"cloud": {
+				Endpoint: config.DefaultEndpoint,
+				Username: "user",
+				Password: "secret",
+				Format:   "json",
+				Send: config.SendDefaults{
+					DeviceID:            "device-1",
+					SimNumber:           lo.ToPtr(uint(2)),
+					DeliveryReport:      lo.ToPtr(false),
+					Priority:            lo.ToPtr(100),
+					TTL:                 lo.ToPtr(90 * time.Minute),
+					SkipPhoneValidation: nil,
+					DeviceActiveWithin:  nil,
+				},
+			},
+			"staging": {Endpoint: "https://staging.example.com/3rdparty/v1"},
+		},
+	}
+	require.NoError(t, src.Save(path))
+
+	file, err := config.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, src, file)
+	assert.Equal(t, []string{"cloud", "staging"}, file.Names())
+
+	profile, err := file.Profile("")
+	require.NoError(t, err)
+	assert.Equal(t, "cloud", profile.Name)
+	assert.Equal(t, "secret", profile.Password)
+	assert.Equal(t, lo.ToPtr(90*time.Minute), profile.Send.TTL)
+
+	_, err = file.Profile("missing")
+	require.ErrorIs(t, err, config.ErrProfileNotFound)
+}
@@ -1,13 +1,30 @@
 package client
 
-import "github.com/android-sms-gateway/client-go/smsgateway"
+import (
+	"net/http"
+
+	"github.com/android-sms-gateway/cli/internal/core/ratelimit"
+	"github.com/android-sms-gateway/cli/internal/core/retry"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+// New creates an API client. A non-empty token takes precedence over basic auth.
+// Requests are throttled and repeated when their context carries a rate limiter or a retry policy.
+func New(username, password, token, endpoint string) *smsgateway.Client {
+	if token != "" {
+		username, password = "", ""
+	}
 
-func New(username, password, endpoint string) *smsgateway.Client {
 	return smsgateway.NewClient(smsgateway.Config{
-		Client:   nil,
+		Client: &http.Client{
+			Transport:     retry.NewTransport(ratelimit.NewTransport(http.DefaultTransport)),
+			CheckRedirect: nil,
+			Jar:           nil,
+			Timeout:       0,
+		},
 		BaseURL:  endpoint,
 		User:     username,
 		Password: password,
-		Token:    "",
+		Token:    token,
 	})
 }
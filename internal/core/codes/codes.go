@@ -6,4 +6,14 @@ const (
 	ClientError
 	OutputError
 	InternalError
+	DeliveryFailed
+	Timeout
+)
+
+// Exit codes of the health command, following the Nagios plugin convention.
+const (
+	HealthOK       = 0
+	HealthWarning  = 1
+	HealthCritical = 2
+	HealthUnknown  = 3
 )
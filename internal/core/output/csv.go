@@ -0,0 +1,140 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/android-sms-gateway/cli/internal/utils/flatten"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+// CSVOutput renders lists as CSV with a header row. Single objects are
+// rendered as key/value rows with flattened keys.
+type CSVOutput struct{}
+
+func NewCSVOutput() *CSVOutput {
+	return &CSVOutput{}
+}
+
+func (o *CSVOutput) MessageState(src smsgateway.MessageState) (string, error) {
+	return o.MessageStates([]smsgateway.MessageState{src})
+}
+
+// MessageStates renders one row per recipient of every message.
+func (*CSVOutput) MessageStates(src []smsgateway.MessageState) (string, error) {
+	rows := make([][]string, 0, len(src))
+	for _, m := range src {
+		if len(m.Recipients) == 0 {
+			rows = append(rows, []string{m.ID, m.DeviceID, string(m.State), "", "", ""})
+			continue
+		}
+
+		for _, r := range m.Recipients {
+			errStr := ""
+			if r.Error != nil {
+				errStr = *r.Error
+			}
+			rows = append(rows, []string{m.ID, m.DeviceID, string(m.State), r.PhoneNumber, string(r.State), errStr})
+		}
+	}
+
+	return writeCSV([]string{"id", "device_id", "state", "phone", "recipient_state", "error"}, rows)
+}
+
+func (*CSVOutput) Logs(src []smsgateway.LogEntry) (string, error) {
+	rows := make([][]string, 0, len(src))
+	for _, entry := range src {
+		rows = append(rows, []string{
+			strconv.FormatUint(entry.ID, 10),
+			string(entry.Priority),
+			entry.Module,
+			entry.Message,
+			entry.CreatedAt.Local().Format(time.RFC3339),
+		})
+	}
+
+	return writeCSV([]string{"id", "priority", "module", "message", "created_at"}, rows)
+}
+
+func (o *CSVOutput) Webhook(src smsgateway.Webhook) (string, error) {
+	return o.Webhooks([]smsgateway.Webhook{src})
+}
+
+func (*CSVOutput) Webhooks(src []smsgateway.Webhook) (string, error) {
+	rows := make([][]string, 0, len(src))
+	for _, w := range src {
+		deviceID := ""
+		if w.DeviceID != nil {
+			deviceID = *w.DeviceID
+		}
+		rows = append(rows, []string{w.ID, w.Event, w.URL, deviceID})
+	}
+
+	return writeCSV([]string{"id", "event", "url", "device_id"}, rows)
+}
+
+func (*CSVOutput) Devices(src []smsgateway.Device) (string, error) {
+	rows := make([][]string, 0, len(src))
+	for _, d := range src {
+		rows = append(rows, []string{
+			d.ID,
+			d.Name,
+			d.LastSeen.Local().Format(time.RFC3339),
+			d.CreatedAt.Local().Format(time.RFC3339),
+		})
+	}
+
+	return writeCSV([]string{"id", "name", "last_seen", "created_at"}, rows)
+}
+
+func (*CSVOutput) Health(src smsgateway.HealthResponse) (string, error) {
+	return writeKeyValues(src)
+}
+
+func (*CSVOutput) Settings(src smsgateway.DeviceSettings) (string, error) {
+	return writeKeyValues(src)
+}
+
+func (*CSVOutput) Token(src smsgateway.TokenResponse) (string, error) {
+	return writeKeyValues(src)
+}
+
+func (*CSVOutput) Success() (string, error) {
+	return "", nil
+}
+
+// writeKeyValues renders v as sorted key/value rows using its flattened JSON representation.
+func writeKeyValues(v any) (string, error) {
+	values, err := flatten.Flatten(v)
+	if err != nil {
+		return "", fmt.Errorf("flatten value: %w", err)
+	}
+
+	rows := make([][]string, 0, len(values))
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		rows = append(rows, []string{key, values[key]})
+	}
+
+	return writeCSV([]string{"key", "value"}, rows)
+}
+
+func writeCSV(header []string, rows [][]string) (string, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+
+	if err := w.Write(header); err != nil {
+		return "", fmt.Errorf("write csv header: %w", err)
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return "", fmt.Errorf("write csv rows: %w", err)
+	}
+
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+var _ Renderer = (*CSVOutput)(nil)
@@ -27,6 +27,10 @@ func (o *JSONOutput) MessageState(src smsgateway.MessageState) (string, error) {
 	return o.marshaler(src)
 }
 
+func (o *JSONOutput) MessageStates(src []smsgateway.MessageState) (string, error) {
+	return o.marshaler(src)
+}
+
 func (o *JSONOutput) Logs(src []smsgateway.LogEntry) (string, error) {
 	return o.marshaler(src)
 }
@@ -39,6 +43,22 @@ func (o *JSONOutput) Webhooks(src []smsgateway.Webhook) (string, error) {
 	return o.marshaler(src)
 }
 
+func (o *JSONOutput) Devices(src []smsgateway.Device) (string, error) {
+	return o.marshaler(src)
+}
+
+func (o *JSONOutput) Health(src smsgateway.HealthResponse) (string, error) {
+	return o.marshaler(src)
+}
+
+func (o *JSONOutput) Settings(src smsgateway.DeviceSettings) (string, error) {
+	return o.marshaler(src)
+}
+
+func (o *JSONOutput) Token(src smsgateway.TokenResponse) (string, error) {
+	return o.marshaler(src)
+}
+
 func (o *JSONOutput) Success() (string, error) {
 	return "", nil
 }
@@ -13,13 +13,19 @@ const (
 	JSON  Format = "json"
 	RAW   Format = "raw"
 	Table Format = "table"
+	CSV   Format = "csv"
 )
 
 type Renderer interface {
 	MessageState(src smsgateway.MessageState) (string, error)
+	MessageStates(src []smsgateway.MessageState) (string, error)
 	Logs(src []smsgateway.LogEntry) (string, error)
 	Webhook(src smsgateway.Webhook) (string, error)
 	Webhooks(src []smsgateway.Webhook) (string, error)
+	Devices(src []smsgateway.Device) (string, error)
+	Health(src smsgateway.HealthResponse) (string, error)
+	Settings(src smsgateway.DeviceSettings) (string, error)
+	Token(src smsgateway.TokenResponse) (string, error)
 	Success() (string, error)
 }
 
@@ -37,6 +43,8 @@ func New(format Format) (Renderer, error) {
 		return NewRawOutput(), nil
 	case Table:
 		return NewTableOutput(), nil
+	case CSV:
+		return NewCSVOutput(), nil
 	default:
 		return nil, ErrUnsupportedFormat
 	}
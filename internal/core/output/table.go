@@ -2,10 +2,13 @@ package output
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"text/tabwriter"
 	"time"
 
+	"github.com/android-sms-gateway/cli/internal/utils/flatten"
 	"github.com/android-sms-gateway/client-go/smsgateway"
 )
 
@@ -69,6 +72,38 @@ func (*TableOutput) MessageState(src smsgateway.MessageState) (string, error) {
 	return strings.TrimRight(b.String(), "\n"), nil
 }
 
+// MessageStates renders one row per recipient of every message.
+func (*TableOutput) MessageStates(src []smsgateway.MessageState) (string, error) {
+	if len(src) == 0 {
+		return EmptyResult, nil
+	}
+
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 0, tabwriterPadding, ' ', 0)
+
+	fmt.Fprintln(tw, "ID\tSTATE\tPHONE\tRECIPIENT STATE\tERROR")
+	for _, m := range src {
+		if len(m.Recipients) == 0 {
+			fmt.Fprintf(tw, "%s\t%s\t\t\t\n", m.ID, m.State)
+			continue
+		}
+
+		for _, r := range m.Recipients {
+			errStr := ""
+			if r.Error != nil {
+				errStr = *r.Error
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", m.ID, m.State, r.PhoneNumber, r.State, errStr)
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return "", fmt.Errorf("flush tabwriter: %w", err)
+	}
+
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
 func (*TableOutput) Logs(src []smsgateway.LogEntry) (string, error) {
 	if len(src) == 0 {
 		return EmptyResult, nil
@@ -135,6 +170,91 @@ func (o *TableOutput) Webhooks(src []smsgateway.Webhook) (string, error) {
 	return strings.TrimRight(b.String(), "\n"), nil
 }
 
+func (*TableOutput) Devices(src []smsgateway.Device) (string, error) {
+	if len(src) == 0 {
+		return EmptyResult, nil
+	}
+
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 0, tabwriterPadding, ' ', 0)
+
+	fmt.Fprintln(tw, "ID\tNAME\tLAST SEEN\tCREATED AT")
+	for _, d := range src {
+		fmt.Fprintf(
+			tw,
+			"%s\t%s\t%s\t%s\n",
+			d.ID,
+			d.Name,
+			d.LastSeen.Local().Format(time.RFC3339),
+			d.CreatedAt.Local().Format(time.RFC3339),
+		)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return "", fmt.Errorf("flush tabwriter: %w", err)
+	}
+
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func (*TableOutput) Health(src smsgateway.HealthResponse) (string, error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Status:\t%s\n", src.Status)
+	fmt.Fprintf(&b, "Version:\t%s\n", src.Version)
+	fmt.Fprintf(&b, "Release ID:\t%d\n", src.ReleaseID)
+
+	if len(src.Checks) > 0 {
+		b.WriteString("\nChecks:\n")
+		tw := tabwriter.NewWriter(&b, 0, 0, tabwriterPadding, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tSTATUS\tVALUE\tDESCRIPTION")
+		for _, name := range slices.Sorted(maps.Keys(src.Checks)) {
+			check := src.Checks[name]
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", name, check.Status, observedValue(check), check.Description)
+		}
+		if err := tw.Flush(); err != nil {
+			return "", fmt.Errorf("flush tabwriter: %w", err)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func (*TableOutput) Settings(src smsgateway.DeviceSettings) (string, error) {
+	values, err := flatten.Flatten(src)
+	if err != nil {
+		return "", fmt.Errorf("flatten settings: %w", err)
+	}
+	if len(values) == 0 {
+		return EmptyResult, nil
+	}
+
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 0, tabwriterPadding, ' ', 0)
+
+	fmt.Fprintln(tw, "KEY\tVALUE")
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		fmt.Fprintf(tw, "%s\t%s\n", key, values[key])
+	}
+
+	if err := tw.Flush(); err != nil {
+		return "", fmt.Errorf("flush tabwriter: %w", err)
+	}
+
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func (*TableOutput) Token(src smsgateway.TokenResponse) (string, error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "ID:\t%s\n", src.ID)
+	fmt.Fprintf(&b, "Token Type:\t%s\n", src.TokenType)
+	fmt.Fprintf(&b, "Access Token:\t%s\n", src.AccessToken)
+	fmt.Fprintf(&b, "Expires At:\t%s\n", src.ExpiresAt.Local().Format(time.RFC3339))
+
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
 func (*TableOutput) Success() (string, error) {
 	return "Success", nil
 }
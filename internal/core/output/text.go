@@ -2,10 +2,13 @@ package output
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/android-sms-gateway/cli/internal/utils/flatten"
 	"github.com/android-sms-gateway/client-go/smsgateway"
 )
 
@@ -71,6 +74,31 @@ func (*TextOutput) MessageState(src smsgateway.MessageState) (string, error) {
 	return builder.String(), nil
 }
 
+// MessageStates formats a slice of message states separated by "---".
+func (o *TextOutput) MessageStates(src []smsgateway.MessageState) (string, error) {
+	if len(src) == 0 {
+		return EmptyResult, nil
+	}
+
+	builder := strings.Builder{}
+
+	for i, state := range src {
+		b, err := o.MessageState(state)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(b)
+
+		if i == len(src)-1 {
+			continue
+		}
+
+		builder.WriteString("\n---\n")
+	}
+
+	return builder.String(), nil
+}
+
 func (*TextOutput) Logs(src []smsgateway.LogEntry) (string, error) {
 	if len(src) == 0 {
 		return EmptyResult, nil
@@ -148,6 +176,97 @@ func (o *TextOutput) Webhooks(src []smsgateway.Webhook) (string, error) {
 	return builder.String(), nil
 }
 
+// Devices formats a slice of devices separated by "---".
+func (*TextOutput) Devices(src []smsgateway.Device) (string, error) {
+	if len(src) == 0 {
+		return EmptyResult, nil
+	}
+
+	builder := strings.Builder{}
+	for i, d := range src {
+		builder.WriteString("ID: ")
+		builder.WriteString(d.ID)
+		builder.WriteString("\nName: ")
+		builder.WriteString(d.Name)
+		builder.WriteString("\nLast Seen: ")
+		builder.WriteString(d.LastSeen.Local().Format(time.RFC3339))
+		builder.WriteString("\nCreated At: ")
+		builder.WriteString(d.CreatedAt.Local().Format(time.RFC3339))
+
+		if i < len(src)-1 {
+			builder.WriteString("\n---\n")
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// Health formats the gateway health status and its checks sorted by name.
+func (*TextOutput) Health(src smsgateway.HealthResponse) (string, error) {
+	builder := strings.Builder{}
+	builder.WriteString("Status: ")
+	builder.WriteString(string(src.Status))
+	builder.WriteString("\nVersion: ")
+	builder.WriteString(src.Version)
+	builder.WriteString("\nRelease ID: ")
+	builder.WriteString(strconv.Itoa(src.ReleaseID))
+
+	if len(src.Checks) > 0 {
+		builder.WriteString("\nChecks:")
+		for _, name := range slices.Sorted(maps.Keys(src.Checks)) {
+			check := src.Checks[name]
+			builder.WriteString("\n\t")
+			builder.WriteString(name)
+			builder.WriteString("\t")
+			builder.WriteString(string(check.Status))
+			builder.WriteString("\t")
+			builder.WriteString(observedValue(check))
+			builder.WriteString("\t")
+			builder.WriteString(check.Description)
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// Settings formats the settings as sorted "section.key: value" lines.
+func (*TextOutput) Settings(src smsgateway.DeviceSettings) (string, error) {
+	values, err := flatten.Flatten(src)
+	if err != nil {
+		return "", fmt.Errorf("flatten settings: %w", err)
+	}
+	if len(values) == 0 {
+		return EmptyResult, nil
+	}
+
+	builder := strings.Builder{}
+	for i, key := range slices.Sorted(maps.Keys(values)) {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(key)
+		builder.WriteString(": ")
+		builder.WriteString(values[key])
+	}
+
+	return builder.String(), nil
+}
+
+// Token formats an issued access token into a string representation.
+func (*TextOutput) Token(src smsgateway.TokenResponse) (string, error) {
+	builder := strings.Builder{}
+	builder.WriteString("ID: ")
+	builder.WriteString(src.ID)
+	builder.WriteString("\nToken Type: ")
+	builder.WriteString(src.TokenType)
+	builder.WriteString("\nAccess Token: ")
+	builder.WriteString(src.AccessToken)
+	builder.WriteString("\nExpires At: ")
+	builder.WriteString(src.ExpiresAt.Local().Format(time.RFC3339))
+
+	return builder.String(), nil
+}
+
 // Success returns a string indicating success.
 func (*TextOutput) Success() (string, error) {
 	return "Success", nil
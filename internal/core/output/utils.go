@@ -1,8 +1,23 @@
 package output
 
+import (
+	"strconv"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
 func boolToString(b bool) string {
 	if b {
 		return "true"
 	}
 	return "false"
 }
+
+// observedValue formats the observed value of a health check with its unit.
+func observedValue(check smsgateway.HealthCheck) string {
+	if check.ObservedUnit == "" {
+		return strconv.Itoa(check.ObservedValue)
+	}
+
+	return strconv.Itoa(check.ObservedValue) + " " + check.ObservedUnit
+}
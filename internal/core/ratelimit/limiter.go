@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// minLimit is the lowest rate the limiter slows down to after 429 responses.
+	minLimit = rate.Limit(0.1)
+	// minSlowDownWindow is the shortest time after halving the rate in which
+	// further 429 responses, usually to requests already in flight, don't halve it again.
+	minSlowDownWindow = time.Second
+	// recoveryInterval is the time without 429 responses after which the rate is raised.
+	recoveryInterval = 10 * time.Second
+	// recoverySteps is the number of equal steps in which the rate is raised back.
+	recoverySteps = 10
+)
+
+// Limiter is a token bucket shared by concurrent senders. When the server
+// answers 429 Too Many Requests, every request is paused for the Retry-After
+// delay and the rate is halved, at most once per delay. Without further 429
+// responses the rate is raised back gradually to the initial one.
+type Limiter struct {
+	limiter *rate.Limiter
+	initial rate.Limit
+
+	mu       sync.Mutex
+	resumeAt time.Time
+	// windowEnd is the end of the window in which the rate isn't halved again.
+	windowEnd time.Time
+	// changedAt is the time the rate was last lowered or raised.
+	changedAt time.Time
+}
+
+// New creates a limiter allowing limit requests per second with the burst size.
+// Use rate.Inf to only react to 429 responses.
+func New(limit rate.Limit, burst int) *Limiter {
+	return &Limiter{
+		limiter:   rate.NewLimiter(limit, burst),
+		initial:   limit,
+		mu:        sync.Mutex{},
+		resumeAt:  time.Time{},
+		windowEnd: time.Time{},
+		changedAt: time.Time{},
+	}
+}
+
+// Wait blocks until the pause is over and a token is available.
+func (l *Limiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	l.recover(time.Now())
+	pause := time.Until(l.resumeAt)
+	l.mu.Unlock()
+
+	if pause > 0 {
+		timer := time.NewTimer(pause)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait for rate limit: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+
+	if err := l.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("wait for rate limit: %w", err)
+	}
+
+	return nil
+}
+
+// SlowDown pauses all requests for the delay and halves the rate, unless it
+// was already halved within the previous delay.
+func (l *Limiter) SlowDown(delay time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if resumeAt := now.Add(delay); resumeAt.After(l.resumeAt) {
+		l.resumeAt = resumeAt
+	}
+
+	limit := l.limiter.Limit()
+	if limit == rate.Inf || now.Before(l.windowEnd) {
+		return
+	}
+
+	l.limiter.SetLimitAt(now, max(limit/2, minLimit)) //nolint:mnd // halve the rate
+	l.windowEnd = now.Add(max(delay, minSlowDownWindow))
+	l.changedAt = now
+}
+
+// recover raises a lowered rate by a step of the initial one for every
+// recoveryInterval without 429 responses.
+func (l *Limiter) recover(now time.Time) {
+	limit := l.limiter.Limit()
+	if limit >= l.initial || now.Sub(l.changedAt) < recoveryInterval {
+		return
+	}
+
+	l.limiter.SetLimitAt(now, min(limit+l.initial/recoverySteps, l.initial))
+	l.changedAt = now
+}
+
+// Limit returns the current rate in requests per second.
+func (l *Limiter) Limit() rate.Limit {
+	return l.limiter.Limit()
+}
+
+type limiterKey struct{}
+
+// WithLimiter returns a context throttling the requests made with it. The
+// limiters of the parent context keep applying, so a request can be throttled
+// by a shared limiter and a narrower one at the same time.
+func WithLimiter(ctx context.Context, l *Limiter) context.Context {
+	if l == nil {
+		return ctx
+	}
+
+	parent := fromContext(ctx)
+	limiters := make([]*Limiter, 0, len(parent)+1)
+	limiters = append(limiters, parent...)
+
+	return context.WithValue(ctx, limiterKey{}, append(limiters, l))
+}
+
+func fromContext(ctx context.Context) []*Limiter {
+	limiters, _ := ctx.Value(limiterKey{}).([]*Limiter)
+	return limiters
+}
@@ -0,0 +1,48 @@
+package ratelimit
+
+import (
+	"net/http"
+
+	"github.com/android-sms-gateway/cli/internal/utils/httputil"
+)
+
+// Transport throttles requests whose context carries limiters, and slows the
+// limiters down when the server answers 429 Too Many Requests. The response is
+// returned as is, repeating the request is left to retry.Transport, so the
+// attempts are counted in one place. Other requests are passed through unchanged.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport wraps base, http.DefaultTransport is used when base is nil.
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &Transport{Base: base}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	limiters := fromContext(req.Context())
+	for _, limiter := range limiters {
+		if err := limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err //nolint:wrapcheck // errors are wrapped by http.Client
+	}
+
+	delay, ok := httputil.RetryAfter(resp.Header)
+	if !ok {
+		delay = httputil.DefaultRetryAfter
+	}
+	for _, limiter := range limiters {
+		limiter.SlowDown(delay)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,206 @@
+package ratelimit_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/android-sms-gateway/cli/internal/core/ratelimit"
+	"github.com/android-sms-gateway/cli/internal/core/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func newServer(t *testing.T, throttled int32, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "payload", string(body))
+
+		if calls.Add(1) <= throttled {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func post(t *testing.T, ctx context.Context, url string) int {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader([]byte("payload")))
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: retry.NewTransport(ratelimit.NewTransport(nil))}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+// withRetries returns a context repeating 429 responses up to 5 times.
+func withRetries(ctx context.Context) (context.Context, *retry.Counter) {
+	return retry.WithPolicy(ctx, retry.Policy{Retries: 0, Backoff: 0, Jitter: 0, RateLimitRetries: 5})
+}
+
+func TestTransport_SlowsDownOnTooManyRequests(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := newServer(t, 1, &calls)
+
+	limiter := ratelimit.New(10, 1)
+	status := post(t, ratelimit.WithLimiter(context.Background(), limiter), server.URL)
+
+	// the request isn't repeated without a retry policy
+	assert.Equal(t, http.StatusTooManyRequests, status)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.InDelta(t, 5.0, float64(limiter.Limit()), 1e-9)
+}
+
+func TestTransport_RetriesTooManyRequests(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := newServer(t, 2, &calls)
+
+	limiter := ratelimit.New(10, 1)
+	ctx, counter := withRetries(ratelimit.WithLimiter(context.Background(), limiter))
+	status := post(t, ctx, server.URL)
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, 3, counter.Attempts())
+	// the second 429 response comes within the window of the first one
+	assert.InDelta(t, 5.0, float64(limiter.Limit()), 1e-9)
+}
+
+func TestTransport_NestedLimiters(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := newServer(t, 1, &calls)
+
+	shared := ratelimit.New(100, 1)
+	device := ratelimit.New(20, 1)
+	ctx, _ := withRetries(ratelimit.WithLimiter(ratelimit.WithLimiter(context.Background(), shared), device))
+
+	start := time.Now()
+	status := post(t, ctx, server.URL)
+	status2 := post(t, ctx, server.URL)
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, http.StatusOK, status2)
+	assert.Equal(t, int32(3), calls.Load())
+	// the 429 response slows down both limiters, the narrower one paces the requests
+	assert.InDelta(t, 50.0, float64(shared.Limit()), 1e-9)
+	assert.InDelta(t, 10.0, float64(device.Limit()), 1e-9)
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestTransport_GivesUp(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := newServer(t, 100, &calls)
+
+	ctx, counter := withRetries(ratelimit.WithLimiter(context.Background(), ratelimit.New(rate.Inf, 1)))
+	status := post(t, ctx, server.URL)
+
+	assert.Equal(t, http.StatusTooManyRequests, status)
+	assert.Equal(t, int32(6), calls.Load())
+	assert.Equal(t, 6, counter.Attempts())
+}
+
+func TestTransport_WithoutLimiter(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := newServer(t, 1, &calls)
+
+	status := post(t, context.Background(), server.URL)
+
+	assert.Equal(t, http.StatusTooManyRequests, status)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	t.Parallel()
+
+	limiter := ratelimit.New(20, 1)
+
+	start := time.Now()
+	for range 3 {
+		require.NoError(t, limiter.Wait(context.Background()))
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestLimiter_SlowDown(t *testing.T) {
+	t.Parallel()
+
+	limiter := ratelimit.New(rate.Inf, 1)
+	limiter.SlowDown(100 * time.Millisecond)
+
+	start := time.Now()
+	require.NoError(t, limiter.Wait(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	assert.Equal(t, rate.Inf, limiter.Limit())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	limiter.SlowDown(time.Minute)
+	require.ErrorIs(t, limiter.Wait(ctx), context.Canceled)
+}
+
+func TestLimiter_SlowDownOncePerWindow(t *testing.T) {
+	t.Parallel()
+
+	synctest.Test(t, func(t *testing.T) {
+		limiter := ratelimit.New(8, 1)
+
+		limiter.SlowDown(2 * time.Second)
+		limiter.SlowDown(2 * time.Second)
+		assert.InDelta(t, 4.0, float64(limiter.Limit()), 1e-9)
+
+		time.Sleep(2 * time.Second)
+		limiter.SlowDown(0)
+		assert.InDelta(t, 2.0, float64(limiter.Limit()), 1e-9)
+	})
+}
+
+func TestLimiter_Recovers(t *testing.T) {
+	t.Parallel()
+
+	synctest.Test(t, func(t *testing.T) {
+		limiter := ratelimit.New(10, 1)
+		limiter.SlowDown(0)
+		require.InDelta(t, 5.0, float64(limiter.Limit()), 1e-9)
+
+		// the rate isn't raised before the recovery interval
+		time.Sleep(5 * time.Second)
+		require.NoError(t, limiter.Wait(context.Background()))
+		assert.InDelta(t, 5.0, float64(limiter.Limit()), 1e-9)
+
+		// then it is raised in steps of a tenth up to the initial rate
+		for _, want := range []float64{6, 7, 8, 9, 10, 10} {
+			time.Sleep(10 * time.Second)
+			require.NoError(t, limiter.Wait(context.Background()))
+			assert.InDelta(t, want, float64(limiter.Limit()), 1e-9)
+		}
+	})
+}
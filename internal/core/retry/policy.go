@@ -0,0 +1,70 @@
+package retry
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// maxBackoff caps the delay between attempts.
+const maxBackoff = time.Minute
+
+// Policy describes how requests failed with a transient error are repeated.
+type Policy struct {
+	// Retries is the number of repeats after the first attempt.
+	Retries int
+	// Backoff is the delay before the first repeat, doubled for every next one.
+	Backoff time.Duration
+	// Jitter randomizes the delay by up to the fraction in both directions, from 0 to 1.
+	Jitter float64
+	// RateLimitRetries is the number of repeats after 429 Too Many Requests, made
+	// after the Retry-After delay before Retries are used.
+	RateLimitRetries int
+}
+
+// Delay returns the pause before the attempt, counting from 1 for the first repeat.
+func (p Policy) Delay(attempt int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxBackoff)
+
+	if p.Jitter > 0 {
+		//nolint:gosec,mnd // jitter doesn't need a cryptographic source; spread the factor to [-1, 1)
+		factor := 1 + p.Jitter*(rand.Float64()*2-1)
+		delay = time.Duration(float64(delay) * factor)
+	}
+
+	return delay
+}
+
+// Counter counts the attempts of the requests made with the context.
+type Counter struct {
+	attempts atomic.Int32
+}
+
+// Attempts returns the number of attempts made.
+func (c *Counter) Attempts() int {
+	return int(c.attempts.Load())
+}
+
+type contextKey struct{}
+
+type contextValue struct {
+	policy  Policy
+	counter *Counter
+}
+
+// WithPolicy returns a context repeating the requests made with it according to
+// the policy, and the counter of their attempts.
+func WithPolicy(ctx context.Context, policy Policy) (context.Context, *Counter) {
+	counter := &Counter{attempts: atomic.Int32{}}
+	return context.WithValue(ctx, contextKey{}, contextValue{policy: policy, counter: counter}), counter
+}
+
+func fromContext(ctx context.Context) (contextValue, bool) {
+	v, ok := ctx.Value(contextKey{}).(contextValue)
+	return v, ok
+}
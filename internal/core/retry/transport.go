@@ -0,0 +1,124 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/android-sms-gateway/cli/internal/utils/httputil"
+)
+
+// Transport repeats requests whose context carries a Policy when they fail with
+// a transient error: a timeout, a connection reset, 429 Too Many Requests or a 5xx
+// status. Other requests are passed through unchanged.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport wraps base, http.DefaultTransport is used when base is nil.
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &Transport{Base: base}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	value, ok := fromContext(req.Context())
+	if !ok {
+		return t.Base.RoundTrip(req) //nolint:wrapcheck // errors are wrapped by http.Client
+	}
+
+	retries, throttled := 0, 0
+	for {
+		value.counter.attempts.Add(1)
+
+		resp, err := t.Base.RoundTrip(req)
+		if !isTransient(req.Context(), resp, err) || !httputil.CanRewind(req) {
+			return resp, err //nolint:wrapcheck // errors are wrapped by http.Client
+		}
+
+		var (
+			delay         time.Duration
+			retryAfter    time.Duration
+			hasRetryAfter bool
+		)
+		if resp != nil {
+			retryAfter, hasRetryAfter = httputil.RetryAfter(resp.Header)
+		}
+
+		switch {
+		case resp != nil && resp.StatusCode == http.StatusTooManyRequests && throttled < value.policy.RateLimitRetries:
+			throttled++
+			delay = httputil.DefaultRetryAfter
+			if hasRetryAfter {
+				delay = retryAfter
+			}
+		case retries < value.policy.Retries:
+			retries++
+			delay = max(value.policy.Delay(retries), retryAfter)
+		default:
+			return resp, err //nolint:wrapcheck // errors are wrapped by http.Client
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		if waitErr := wait(req.Context(), delay); waitErr != nil {
+			return nil, waitErr
+		}
+
+		if req, err = httputil.Rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isTransient reports whether the request may succeed when repeated.
+func isTransient(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return true
+		}
+
+		return errors.Is(err, syscall.ECONNRESET) ||
+			errors.Is(err, syscall.ECONNABORTED) ||
+			errors.Is(err, syscall.EPIPE) ||
+			errors.Is(err, io.EOF) ||
+			errors.Is(err, io.ErrUnexpectedEOF)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode == http.StatusNotImplemented:
+		return false
+	default:
+		return resp.StatusCode >= http.StatusInternalServerError
+	}
+}
+
+func wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("wait before retry: %w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,178 @@
+package retry_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/cli/internal/core/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fastPolicy = retry.Policy{Retries: 3, Backoff: time.Millisecond, Jitter: 0, RateLimitRetries: 0}
+
+// newServer answers with the statuses in order, repeating the last one.
+func newServer(t *testing.T, calls *atomic.Int32, statuses ...int) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "payload", string(body))
+
+		call := int(calls.Add(1))
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(statuses[min(call, len(statuses))-1])
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func post(t *testing.T, ctx context.Context, url string) (int, error) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader([]byte("payload")))
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: retry.NewTransport(nil)}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+func TestTransport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		statuses []int
+		status   int
+		attempts int
+	}{
+		{name: "success", statuses: []int{http.StatusAccepted}, status: http.StatusAccepted, attempts: 1},
+		{
+			name:     "server errors",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusAccepted},
+			status:   http.StatusAccepted,
+			attempts: 3,
+		},
+		{
+			name:     "too many requests",
+			statuses: []int{http.StatusTooManyRequests, http.StatusAccepted},
+			status:   http.StatusAccepted,
+			attempts: 2,
+		},
+		{name: "validation error", statuses: []int{http.StatusBadRequest}, status: http.StatusBadRequest, attempts: 1},
+		{name: "not implemented", statuses: []int{http.StatusNotImplemented}, status: http.StatusNotImplemented, attempts: 1},
+		{
+			name:     "retries exhausted",
+			statuses: []int{http.StatusInternalServerError},
+			status:   http.StatusInternalServerError,
+			attempts: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+			server := newServer(t, &calls, tt.statuses...)
+
+			ctx, counter := retry.WithPolicy(context.Background(), fastPolicy)
+			status, err := post(t, ctx, server.URL)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.attempts, counter.Attempts())
+			assert.Equal(t, int32(tt.attempts), calls.Load())
+		})
+	}
+}
+
+func TestTransport_RateLimitRetries(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := newServer(
+		t,
+		&calls,
+		http.StatusTooManyRequests,
+		http.StatusTooManyRequests,
+		http.StatusTooManyRequests,
+		http.StatusServiceUnavailable,
+	)
+
+	// 429 responses use up the rate limit retries first, then the regular ones
+	policy := retry.Policy{Retries: 1, Backoff: time.Millisecond, Jitter: 0, RateLimitRetries: 2}
+	ctx, counter := retry.WithPolicy(context.Background(), policy)
+	status, err := post(t, ctx, server.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, 4, counter.Attempts())
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestTransport_ConnectionReset(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			_ = conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, counter := retry.WithPolicy(context.Background(), fastPolicy)
+	status, err := post(t, ctx, server.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.Equal(t, 2, counter.Attempts())
+}
+
+func TestTransport_WithoutPolicy(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := newServer(t, &calls, http.StatusServiceUnavailable)
+
+	status, err := post(t, context.Background(), server.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestPolicy_Delay(t *testing.T) {
+	t.Parallel()
+
+	policy := retry.Policy{Retries: 10, Backoff: time.Second, Jitter: 0}
+	assert.Equal(t, time.Second, policy.Delay(1))
+	assert.Equal(t, 2*time.Second, policy.Delay(2))
+	assert.Equal(t, 8*time.Second, policy.Delay(4))
+	assert.Equal(t, time.Minute, policy.Delay(10))
+
+	policy.Jitter = 0.5
+	for range 100 {
+		delay := policy.Delay(2)
+		assert.GreaterOrEqual(t, delay, time.Second)
+		assert.LessOrEqual(t, delay, 3*time.Second)
+	}
+}
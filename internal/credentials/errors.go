@@ -0,0 +1,9 @@
+package credentials
+
+import "errors"
+
+var (
+	ErrCorrupted          = errors.New("credentials file is corrupted")
+	ErrDecryptionFailed   = errors.New("failed to decrypt credentials, check the passphrase")
+	ErrPassphraseRequired = errors.New("credentials are protected with a passphrase")
+)
@@ -0,0 +1,272 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	storeFileName = "credentials.enc"
+	keyFileName   = "credentials.key"
+
+	formatVersion = 1
+
+	kdfKeyFile = "keyfile"
+	kdfPBKDF2  = "pbkdf2-sha256"
+
+	keySize          = 32
+	saltSize         = 16
+	pbkdf2Iterations = 600_000
+
+	defaultProfile = "default"
+)
+
+// Credentials are the account credentials kept in the store.
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Store keeps credentials per profile in an AES-256-GCM encrypted file.
+// The encryption key is derived from a passphrase when one is provided,
+// otherwise it is read from a random key file next to the store.
+type Store struct {
+	path       string
+	keyPath    string
+	passphrase string
+}
+
+type envelope struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	Salt    []byte `json:"salt,omitempty"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// NewStore creates a store located in dir.
+func NewStore(dir, passphrase string) *Store {
+	return &Store{
+		path:       filepath.Join(dir, storeFileName),
+		keyPath:    filepath.Join(dir, keyFileName),
+		passphrase: passphrase,
+	}
+}
+
+// Path returns the location of the encrypted credentials file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Get returns the credentials saved for the profile.
+// An empty profile name refers to the default entry.
+func (s *Store) Get(profile string) (Credentials, bool, error) {
+	entries, err := s.load()
+	if err != nil {
+		return Credentials{}, false, err
+	}
+
+	creds, ok := entries[entryName(profile)]
+	return creds, ok, nil
+}
+
+// Set saves the credentials for the profile.
+func (s *Store) Set(profile string, creds Credentials) error {
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	entries[entryName(profile)] = creds
+
+	return s.save(entries)
+}
+
+// Delete removes the credentials of the profile. The store files are removed
+// once no credentials are left.
+func (s *Store) Delete(profile string) (bool, error) {
+	entries, err := s.load()
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := entries[entryName(profile)]; !ok {
+		return false, nil
+	}
+	delete(entries, entryName(profile))
+
+	if len(entries) > 0 {
+		return true, s.save(entries)
+	}
+
+	if rmErr := os.Remove(s.path); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+		return false, fmt.Errorf("remove credentials file: %w", rmErr)
+	}
+	if rmErr := os.Remove(s.keyPath); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+		return false, fmt.Errorf("remove key file: %w", rmErr)
+	}
+
+	return true, nil
+}
+
+func (s *Store) load() (map[string]Credentials, error) {
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]Credentials{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read credentials file: %w", err)
+	}
+
+	var env envelope
+	if unmErr := json.Unmarshal(b, &env); unmErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCorrupted, unmErr)
+	}
+	if env.Version != formatVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrCorrupted, env.Version)
+	}
+
+	key, err := s.key(env.KDF, env.Salt, false)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := aead.Open(nil, env.Nonce, env.Data, nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	entries := map[string]Credentials{}
+	if unmErr := json.Unmarshal(plain, &entries); unmErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCorrupted, unmErr)
+	}
+
+	return entries, nil
+}
+
+func (s *Store) save(entries map[string]Credentials) error {
+	plain, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("marshal credentials: %w", err)
+	}
+
+	if mkErr := os.MkdirAll(filepath.Dir(s.path), 0o700); mkErr != nil {
+		return fmt.Errorf("create credentials directory: %w", mkErr)
+	}
+
+	env := envelope{
+		Version: formatVersion,
+		KDF:     kdfKeyFile,
+		Salt:    nil,
+		Nonce:   nil,
+		Data:    nil,
+	}
+	if s.passphrase != "" {
+		env.KDF = kdfPBKDF2
+		env.Salt = make([]byte, saltSize)
+		_, _ = rand.Read(env.Salt)
+	}
+
+	key, err := s.key(env.KDF, env.Salt, true)
+	if err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	env.Nonce = make([]byte, aead.NonceSize())
+	_, _ = rand.Read(env.Nonce)
+	env.Data = aead.Seal(nil, env.Nonce, plain, nil)
+
+	b, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("marshal credentials file: %w", err)
+	}
+
+	if wrErr := os.WriteFile(s.path, b, 0o600); wrErr != nil {
+		return fmt.Errorf("write credentials file: %w", wrErr)
+	}
+
+	return nil
+}
+
+func (s *Store) key(kdf string, salt []byte, create bool) ([]byte, error) {
+	switch kdf {
+	case kdfPBKDF2:
+		if s.passphrase == "" {
+			return nil, ErrPassphraseRequired
+		}
+
+		key, err := pbkdf2.Key(sha256.New, s.passphrase, salt, pbkdf2Iterations, keySize)
+		if err != nil {
+			return nil, fmt.Errorf("derive key: %w", err)
+		}
+		return key, nil
+	case kdfKeyFile:
+		return s.fileKey(create)
+	default:
+		return nil, fmt.Errorf("%w: unsupported key derivation %q", ErrCorrupted, kdf)
+	}
+}
+
+func (s *Store) fileKey(create bool) ([]byte, error) {
+	b, err := os.ReadFile(s.keyPath)
+	if err == nil {
+		key, decErr := hex.DecodeString(strings.TrimSpace(string(b)))
+		if decErr != nil || len(key) != keySize {
+			return nil, fmt.Errorf("%w: invalid key file %s", ErrCorrupted, s.keyPath)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) || !create {
+		return nil, fmt.Errorf("read key file: %w", err)
+	}
+
+	key := make([]byte, keySize)
+	_, _ = rand.Read(key)
+	if wrErr := os.WriteFile(s.keyPath, []byte(hex.EncodeToString(key)), 0o600); wrErr != nil {
+		return nil, fmt.Errorf("write key file: %w", wrErr)
+	}
+
+	return key, nil
+}
+
+func entryName(profile string) string {
+	if profile == "" {
+		return defaultProfile
+	}
+
+	return profile
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+
+	return aead, nil
+}
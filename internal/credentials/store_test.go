@@ -0,0 +1,60 @@
+package credentials_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/android-sms-gateway/cli/internal/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_KeyFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := credentials.NewStore(dir, "")
+
+	require.NoError(t, store.Set("", credentials.Credentials{Username: "user", Password: "secret"}))
+
+	info, err := os.Stat(store.Path())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	raw, err := os.ReadFile(store.Path())
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "secret")
+
+	creds, ok, err := credentials.NewStore(dir, "").Get("")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, credentials.Credentials{Username: "user", Password: "secret"}, creds)
+
+	deleted, err := store.Delete("")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.NoFileExists(t, store.Path())
+	assert.NoFileExists(t, filepath.Join(dir, "credentials.key"))
+}
+
+func TestStore_Passphrase(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, credentials.NewStore(dir, "passphrase").Set(
+		"work",
+		credentials.Credentials{Username: "user", Password: "secret"},
+	))
+
+	creds, ok, err := credentials.NewStore(dir, "passphrase").Get("work")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "secret", creds.Password)
+
+	_, _, err = credentials.NewStore(dir, "").Get("work")
+	require.ErrorIs(t, err, credentials.ErrPassphraseRequired)
+
+	_, _, err = credentials.NewStore(dir, "wrong").Get("work")
+	require.ErrorIs(t, err, credentials.ErrDecryptionFailed)
+}
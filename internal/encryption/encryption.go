@@ -0,0 +1,189 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // required by the gateway encryption format
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/android-sms-gateway/client-go/smsgateway"
+)
+
+const (
+	algorithm = "aes-256-cbc/pbkdf2-sha1"
+
+	defaultIterations = 75_000
+	keySize           = 32
+	saltSize          = aes.BlockSize
+
+	// $<algorithm>$i=<iterations>$<salt>$<ciphertext>.
+	partsCount = 5
+)
+
+// Encryptor encrypts and decrypts values with a passphrase shared with the device,
+// compatible with the end-to-end encryption of the gateway apps.
+type Encryptor struct {
+	passphrase string
+	iterations int
+}
+
+// New creates an encryptor for the passphrase.
+func New(passphrase string) *Encryptor {
+	return &Encryptor{
+		passphrase: passphrase,
+		iterations: defaultIterations,
+	}
+}
+
+// Encrypt encrypts the value with AES-256-CBC, the key is derived with PBKDF2-SHA1.
+func (e *Encryptor) Encrypt(value string) (string, error) {
+	salt := make([]byte, saltSize)
+	_, _ = rand.Read(salt)
+
+	block, err := e.newBlock(salt, e.iterations)
+	if err != nil {
+		return "", err
+	}
+
+	plain := pad([]byte(value), aes.BlockSize)
+	encrypted := make([]byte, len(plain))
+	cipher.NewCBCEncrypter(block, salt).CryptBlocks(encrypted, plain)
+
+	return fmt.Sprintf(
+		"$%s$i=%d$%s$%s",
+		algorithm,
+		e.iterations,
+		base64.StdEncoding.EncodeToString(salt),
+		base64.StdEncoding.EncodeToString(encrypted),
+	), nil
+}
+
+// Decrypt decrypts a value produced by Encrypt or by the device.
+func (e *Encryptor) Decrypt(value string) (string, error) {
+	parts := strings.Split(value, "$")
+	if len(parts) != partsCount || parts[0] != "" || parts[1] != algorithm {
+		return "", ErrInvalidFormat
+	}
+
+	iterations, err := strconv.Atoi(strings.TrimPrefix(parts[2], "i="))
+	if err != nil || iterations < 1 {
+		return "", fmt.Errorf("%w: invalid iterations %q", ErrInvalidFormat, parts[2])
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil || len(salt) != saltSize {
+		return "", fmt.Errorf("%w: invalid salt", ErrInvalidFormat)
+	}
+
+	encrypted, err := base64.StdEncoding.DecodeString(parts[4])
+	if err != nil || len(encrypted) == 0 || len(encrypted)%aes.BlockSize != 0 {
+		return "", fmt.Errorf("%w: invalid ciphertext", ErrInvalidFormat)
+	}
+
+	block, err := e.newBlock(salt, iterations)
+	if err != nil {
+		return "", err
+	}
+
+	plain := make([]byte, len(encrypted))
+	cipher.NewCBCDecrypter(block, salt).CryptBlocks(plain, encrypted)
+
+	unpadded, ok := unpad(plain, aes.BlockSize)
+	if !ok {
+		return "", ErrDecryptionFailed
+	}
+
+	return string(unpadded), nil
+}
+
+// EncryptMessage encrypts the text or data payload and the phone numbers of the message
+// and marks it as encrypted.
+func (e *Encryptor) EncryptMessage(msg smsgateway.Message) (smsgateway.Message, error) {
+	var err error
+
+	if msg.TextMessage != nil {
+		text := *msg.TextMessage
+		if text.Text, err = e.Encrypt(text.Text); err != nil {
+			return msg, err
+		}
+		msg.TextMessage = &text
+	}
+	if msg.DataMessage != nil {
+		data := *msg.DataMessage
+		if data.Data, err = e.Encrypt(data.Data); err != nil {
+			return msg, err
+		}
+		msg.DataMessage = &data
+	}
+
+	phones := make([]string, len(msg.PhoneNumbers))
+	for i, phone := range msg.PhoneNumbers {
+		if phones[i], err = e.Encrypt(phone); err != nil {
+			return msg, err
+		}
+	}
+	msg.PhoneNumbers = phones
+	msg.IsEncrypted = true
+
+	return msg, nil
+}
+
+// DecryptState decrypts the recipient phone numbers of an encrypted message state.
+// Hashed values can't be restored and are kept as is.
+func (e *Encryptor) DecryptState(state smsgateway.MessageState) (smsgateway.MessageState, error) {
+	if !state.IsEncrypted || state.IsHashed {
+		return state, nil
+	}
+
+	recipients := make([]smsgateway.RecipientState, len(state.Recipients))
+	for i, r := range state.Recipients {
+		phone, err := e.Decrypt(r.PhoneNumber)
+		if err != nil {
+			return state, err
+		}
+		r.PhoneNumber = phone
+		recipients[i] = r
+	}
+	state.Recipients = recipients
+
+	return state, nil
+}
+
+func (e *Encryptor) newBlock(salt []byte, iterations int) (cipher.Block, error) {
+	key, err := pbkdf2.Key(sha1.New, e.passphrase, salt, iterations, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+
+	return block, nil
+}
+
+// pad applies PKCS#7 padding.
+func pad(b []byte, blockSize int) []byte {
+	n := blockSize - len(b)%blockSize
+	return append(b, bytes.Repeat([]byte{byte(n)}, n)...)
+}
+
+// unpad removes PKCS#7 padding, a mismatch means a wrong key.
+func unpad(b []byte, blockSize int) ([]byte, bool) {
+	n := int(b[len(b)-1])
+	if n == 0 || n > blockSize || n > len(b) {
+		return nil, false
+	}
+	if !bytes.Equal(b[len(b)-n:], bytes.Repeat([]byte{byte(n)}, n)) {
+		return nil, false
+	}
+
+	return b[:len(b)-n], true
+}
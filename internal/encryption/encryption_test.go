@@ -0,0 +1,95 @@
+package encryption_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/android-sms-gateway/cli/internal/encryption"
+	"github.com/android-sms-gateway/client-go/smsgateway"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	e := encryption.New("secret")
+
+	encrypted, err := e.Encrypt("Hello, world!")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, "$aes-256-cbc/pbkdf2-sha1$i=75000$"))
+	assert.NotContains(t, encrypted, "Hello")
+
+	decrypted, err := e.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, world!", decrypted)
+
+	again, err := e.Encrypt("Hello, world!")
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again, "salt must be random")
+}
+
+func TestDecrypt_Invalid(t *testing.T) {
+	t.Parallel()
+
+	encrypted, err := encryption.New("secret").Encrypt("Hello")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		value string
+		err   error
+	}{
+		{name: "plain text", value: "Hello", err: encryption.ErrInvalidFormat},
+		{name: "unknown algorithm", value: "$aes-128-gcm$i=1$AAAA$AAAA", err: encryption.ErrInvalidFormat},
+		{name: "invalid iterations", value: strings.Replace(encrypted, "i=75000", "i=x", 1), err: encryption.ErrInvalidFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, decErr := encryption.New("secret").Decrypt(tt.value)
+			require.ErrorIs(t, decErr, tt.err)
+		})
+	}
+
+	// a wrong key is detected by the padding check in most cases, but never yields the plain text
+	decrypted, err := encryption.New("wrong").Decrypt(encrypted)
+	assert.False(t, err == nil && decrypted == "Hello")
+}
+
+func TestEncryptMessage(t *testing.T) {
+	t.Parallel()
+
+	e := encryption.New("secret")
+	msg := smsgateway.Message{
+		TextMessage:  &smsgateway.TextMessage{Text: "Hello"},
+		PhoneNumbers: []string{"+12025550123", "+12025550124"},
+	}
+
+	encrypted, err := e.EncryptMessage(msg)
+	require.NoError(t, err)
+	assert.True(t, encrypted.IsEncrypted)
+	assert.Equal(t, "Hello", msg.TextMessage.Text, "source message must not be modified")
+
+	text, err := e.Decrypt(encrypted.TextMessage.Text)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", text)
+
+	require.Len(t, encrypted.PhoneNumbers, 2)
+	for i, phone := range encrypted.PhoneNumbers {
+		decrypted, decErr := e.Decrypt(phone)
+		require.NoError(t, decErr)
+		assert.Equal(t, msg.PhoneNumbers[i], decrypted)
+	}
+
+	state, err := e.DecryptState(smsgateway.MessageState{
+		IsEncrypted: true,
+		Recipients: []smsgateway.RecipientState{
+			{PhoneNumber: encrypted.PhoneNumbers[0], State: smsgateway.ProcessingStatePending},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "+12025550123", state.Recipients[0].PhoneNumber)
+}
@@ -0,0 +1,8 @@
+package encryption
+
+import "errors"
+
+var (
+	ErrInvalidFormat    = errors.New("invalid encrypted value format")
+	ErrDecryptionFailed = errors.New("failed to decrypt value, check the passphrase")
+)
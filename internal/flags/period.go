@@ -3,6 +3,7 @@ package flags
 import (
 	"time"
 
+	"github.com/android-sms-gateway/cli/internal/core/codes"
 	"github.com/urfave/cli/v2"
 )
 
@@ -36,3 +37,18 @@ func ParsePeriodFlags(c *cli.Context) PeriodFlags {
 		To:   c.Timestamp("to"),
 	}
 }
+
+// ValidatePeriod checks that both ends of the period are set and ordered.
+func ValidatePeriod(c *cli.Context) error {
+	period := ParsePeriodFlags(c)
+
+	if period.From == nil || period.To == nil {
+		return cli.Exit("From and To dates are required", codes.ParamsError)
+	}
+
+	if period.From.After(*period.To) {
+		return cli.Exit("From date must be less than or equal to To date", codes.ParamsError)
+	}
+
+	return nil
+}
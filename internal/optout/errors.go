@@ -0,0 +1,8 @@
+package optout
+
+import "errors"
+
+var (
+	ErrCorrupted   = errors.New("opt-out file is corrupted")
+	ErrInvalidList = errors.New("invalid phone list")
+)
@@ -0,0 +1,56 @@
+package optout
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/android-sms-gateway/cli/pkg/io/tabular"
+)
+
+// ReadCSV reads the phone numbers from the column of a CSV file. The first
+// line is the header when it names the column, a single-column file may have
+// no header at all.
+func ReadCSV(ctx context.Context, path, column string) ([]string, error) {
+	reader := tabular.NewCSVReader(tabular.CSVConfig{Path: path, Input: nil, Delimiter: ',', HasHeader: false})
+
+	var (
+		phones []string
+		key    string
+	)
+	for record, err := range reader.Records(ctx) {
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+
+		if key == "" {
+			key = headerKey(record, column)
+			if key != "" {
+				continue
+			}
+			if len(record.Values) != 1 {
+				return nil, fmt.Errorf("%w: %s has no %q column", ErrInvalidList, path, column)
+			}
+			key = "col_1"
+		}
+
+		if phone := record.Values[key]; phone != "" {
+			phones = append(phones, phone)
+		}
+	}
+
+	return phones, nil
+}
+
+// headerKey returns the key of the column named in the header record.
+func headerKey(record tabular.Record, column string) string {
+	for i := 1; i <= len(record.Values); i++ {
+		key := "col_" + strconv.Itoa(i)
+		if strings.EqualFold(record.Values[key], column) {
+			return key
+		}
+	}
+
+	return ""
+}
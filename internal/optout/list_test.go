@@ -0,0 +1,56 @@
+package optout_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/android-sms-gateway/cli/internal/optout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCSV(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "header",
+			content: "Name,Phone\nAnn,+12025550123\nBob,\nEve, (202) 555-0124 \n",
+			want:    []string{"+12025550123", "(202) 555-0124"},
+		},
+		{
+			name:    "single column without header",
+			content: "+12025550123\n+12025550124\n",
+			want:    []string{"+12025550123", "+12025550124"},
+		},
+		{
+			name:    "missing column",
+			content: "Name,Mobile\nAnn,+12025550123\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "list.csv")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			phones, err := optout.ReadCSV(context.Background(), path, "phone")
+			if tt.wantErr {
+				require.ErrorIs(t, err, optout.ErrInvalidList)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, phones)
+		})
+	}
+}
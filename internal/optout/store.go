@@ -0,0 +1,179 @@
+package optout
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	storeFileName = "optout.json"
+
+	formatVersion = 1
+)
+
+// Entry is a phone number that must not receive messages.
+type Entry struct {
+	Phone  string    `json:"phone"`
+	Reason string    `json:"reason,omitempty"`
+	Added  time.Time `json:"added"`
+}
+
+// Store keeps the opted-out phone numbers in a JSON file shared by all profiles.
+type Store struct {
+	path string
+}
+
+type file struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+}
+
+// NewStore creates a store located in dir.
+func NewStore(dir string) *Store {
+	return &Store{
+		path: filepath.Join(dir, storeFileName),
+	}
+}
+
+// Path returns the location of the opt-out file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// List returns the entries sorted by phone number.
+func (s *Store) List() ([]Entry, error) {
+	entries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, entry)
+	}
+	slices.SortFunc(list, func(a, b Entry) int { return strings.Compare(a.Phone, b.Phone) })
+
+	return list, nil
+}
+
+// Phones returns the set of opted-out phone numbers.
+func (s *Store) Phones() (map[string]struct{}, error) {
+	entries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	phones := make(map[string]struct{}, len(entries))
+	for phone := range entries {
+		phones[phone] = struct{}{}
+	}
+
+	return phones, nil
+}
+
+// Add saves the entries and returns the number of new phone numbers.
+// Numbers already in the store keep their original entry.
+func (s *Store) Add(entries []Entry) (int, error) {
+	stored, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, entry := range entries {
+		if _, ok := stored[entry.Phone]; ok {
+			continue
+		}
+		stored[entry.Phone] = entry
+		added++
+	}
+	if added == 0 {
+		return 0, nil
+	}
+
+	return added, s.save(stored)
+}
+
+// Remove deletes the phone numbers and returns the number of removed entries.
+func (s *Store) Remove(phones []string) (int, error) {
+	stored, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, phone := range phones {
+		if _, ok := stored[phone]; !ok {
+			continue
+		}
+		delete(stored, phone)
+		removed++
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+
+	return removed, s.save(stored)
+}
+
+func (s *Store) load() (map[string]Entry, error) {
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]Entry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read opt-out file: %w", err)
+	}
+
+	var f file
+	if unmErr := json.Unmarshal(b, &f); unmErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCorrupted, unmErr)
+	}
+	if f.Version != formatVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrCorrupted, f.Version)
+	}
+
+	entries := make(map[string]Entry, len(f.Entries))
+	for _, entry := range f.Entries {
+		entries[entry.Phone] = entry
+	}
+
+	return entries, nil
+}
+
+func (s *Store) save(entries map[string]Entry) error {
+	f := file{
+		Version: formatVersion,
+		Entries: make([]Entry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		f.Entries = append(f.Entries, entry)
+	}
+	slices.SortFunc(f.Entries, func(a, b Entry) int { return strings.Compare(a.Phone, b.Phone) })
+
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal opt-out file: %w", err)
+	}
+
+	if mkErr := os.MkdirAll(filepath.Dir(s.path), 0o700); mkErr != nil {
+		return fmt.Errorf("create opt-out directory: %w", mkErr)
+	}
+
+	// write a temporary file first, so an interrupted write doesn't lose the list
+	tmp := s.path + ".tmp"
+	if wrErr := os.WriteFile(tmp, append(b, '\n'), 0o600); wrErr != nil {
+		return fmt.Errorf("write opt-out file: %w", wrErr)
+	}
+	if mvErr := os.Rename(tmp, s.path); mvErr != nil {
+		return fmt.Errorf("write opt-out file: %w", mvErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package optout_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/android-sms-gateway/cli/internal/optout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "smsgate")
+	store := optout.NewStore(dir)
+
+	list, err := store.List()
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	added := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	count, err := store.Add([]optout.Entry{
+		{Phone: "+12025550124", Reason: "STOP", Added: added},
+		{Phone: "+12025550123", Reason: "", Added: added},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	info, err := os.Stat(store.Path())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	count, err = optout.NewStore(dir).Add([]optout.Entry{
+		{Phone: "+12025550124", Reason: "again", Added: added.Add(time.Hour)},
+	})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	list, err = optout.NewStore(dir).List()
+	require.NoError(t, err)
+	assert.Equal(t, []optout.Entry{
+		{Phone: "+12025550123", Reason: "", Added: added},
+		{Phone: "+12025550124", Reason: "STOP", Added: added},
+	}, list)
+
+	count, err = store.Remove([]string{"+12025550123", "+12025550125"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	phones, err := store.Phones()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{"+12025550124": {}}, phones)
+}
+
+func TestStore_Corrupted(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := optout.NewStore(dir)
+	require.NoError(t, os.WriteFile(store.Path(), []byte("not json"), 0o600))
+
+	_, err := store.List()
+	require.ErrorIs(t, err, optout.ErrCorrupted)
+
+	require.NoError(t, os.WriteFile(store.Path(), []byte(`{"version": 2, "entries": []}`), 0o600))
+	_, err = store.Phones()
+	require.ErrorIs(t, err, optout.ErrCorrupted)
+}
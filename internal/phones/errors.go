@@ -0,0 +1,8 @@
+package phones
+
+import "errors"
+
+var (
+	ErrUnknownRegion = errors.New("unknown region")
+	ErrInvalidNumber = errors.New("invalid phone number")
+)
@@ -0,0 +1,115 @@
+package phones
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/nyaruka/phonenumbers"
+)
+
+// Normalizer converts phone numbers to the E.164 format, e.g. "+12025550123".
+// Numbers without the international prefix are read as numbers of the default region.
+type Normalizer struct {
+	// region is the default region, empty when it's not set.
+	region string
+}
+
+// New returns a normalizer for the default region, an ISO 3166-1 alpha-2 code
+// such as "US". Without a default region only international numbers are normalized.
+func New(defaultRegion string) (*Normalizer, error) {
+	code := strings.ToUpper(strings.TrimSpace(defaultRegion))
+	if code != "" && !phonenumbers.GetSupportedRegions()[code] {
+		return nil, fmt.Errorf("%w %q: use an ISO 3166-1 alpha-2 code such as US", ErrUnknownRegion, defaultRegion)
+	}
+
+	return &Normalizer{region: code}, nil
+}
+
+// Regions returns the sorted codes of the supported regions.
+func Regions() []string {
+	return slices.Sorted(maps.Keys(phonenumbers.GetSupportedRegions()))
+}
+
+// Region returns the code of the default region, empty when it's not set.
+func (n *Normalizer) Region() string {
+	return n.region
+}
+
+// Normalize strips the formatting of the number and returns it in the E.164
+// format. Numbers of impossible length for their country are rejected; the
+// allocated ranges are not checked, so new numbers are not rejected. Without a
+// default region, numbers that are not in the international format are returned as is.
+func (n *Normalizer) Normalize(raw string) (string, error) {
+	number := strings.TrimSpace(raw)
+	international := strings.HasPrefix(number, "+")
+	if !international && n.region == "" {
+		return number, nil
+	}
+
+	// the parser accepts letters and extensions, they are not expected in messages
+	if err := checkCharacters(number); err != nil {
+		return "", err
+	}
+
+	parsed, err := phonenumbers.Parse(number, n.region)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %w", ErrInvalidNumber, number, err)
+	}
+	// local numbers can't be dialled from abroad, they lack the area code
+	if phonenumbers.IsPossibleNumberWithReason(parsed) != phonenumbers.IS_POSSIBLE {
+		if international {
+			return "", fmt.Errorf(
+				"%w %q: not a possible number for country code +%d",
+				ErrInvalidNumber,
+				number,
+				parsed.GetCountryCode(),
+			)
+		}
+		return "", fmt.Errorf("%w %q: not a possible number for region %s", ErrInvalidNumber, number, n.region)
+	}
+
+	return phonenumbers.Format(parsed, phonenumbers.E164), nil
+}
+
+// Canonical returns the key under which the formatting variants of the number
+// match: the E.164 form when the number can be normalized, otherwise its digits
+// with the leading plus sign, if any. Unlike Normalize it never fails, so numbers
+// sent without validation are still matched against opt-out lists and duplicates.
+func (n *Normalizer) Canonical(raw string) string {
+	if phone, err := n.Normalize(raw); err == nil && strings.HasPrefix(phone, "+") {
+		return phone
+	}
+
+	var key strings.Builder
+	for i, r := range strings.TrimSpace(raw) {
+		if (r >= '0' && r <= '9') || (r == '+' && i == 0) {
+			key.WriteRune(r)
+		}
+	}
+
+	return key.String()
+}
+
+// checkCharacters accepts the digits of the number, the leading plus sign and
+// the usual separators.
+func checkCharacters(number string) error {
+	digits := 0
+	for i, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '+' && i == 0:
+		case r == ' ', r == '\t', r == '\u00a0', r == '-', r == '(', r == ')', r == '.', r == '/':
+		default:
+			return fmt.Errorf("%w %q: unexpected character %q", ErrInvalidNumber, number, r)
+		}
+	}
+
+	if digits == 0 {
+		return fmt.Errorf("%w %q: no digits", ErrInvalidNumber, number)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package phones_test
+
+import (
+	"testing"
+
+	"github.com/android-sms-gateway/cli/internal/phones"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		region  string
+		raw     string
+		want    string
+		wantErr string
+	}{
+		{name: "us local", region: "US", raw: "(202) 555-0123", want: "+12025550123"},
+		{name: "us trunk prefix", region: "us", raw: "1-202-555-0123", want: "+12025550123"},
+		{name: "us international prefix", region: "US", raw: "011 44 20 7946 0958", want: "+442079460958"},
+		{name: "ru trunk prefix", region: "RU", raw: "8 (916) 123-45-67", want: "+79161234567"},
+		{name: "ru calling code without plus", region: "RU", raw: "79161234567", want: "+79161234567"},
+		{name: "gb trunk prefix", region: "GB", raw: "020 7946 0958", want: "+442079460958"},
+		{name: "it keeps leading zero", region: "IT", raw: "06 1234 5678", want: "+390612345678"},
+		{name: "international formatted", region: "", raw: " +1 (202) 555.0123 ", want: "+12025550123"},
+		{name: "international other region", region: "RU", raw: "+44 20 7946 0958", want: "+442079460958"},
+		{name: "other country", region: "US", raw: "+372 5123 4567", want: "+37251234567"},
+		{name: "unallocated but possible", region: "", raw: "+1 (102) 155-0123", want: "+11021550123"},
+		{name: "national without region", region: "", raw: "(202) 555-0123", want: "(202) 555-0123"},
+		{
+			name:    "us too short",
+			region:  "US",
+			raw:     "555-0123",
+			wantErr: `invalid phone number "555-0123": not a possible number for region US`,
+		},
+		{
+			name:    "impossible for country code",
+			region:  "",
+			raw:     "+1 202 555 012",
+			wantErr: "not a possible number for country code +1",
+		},
+		{
+			name:    "too long",
+			region:  "",
+			raw:     "+1 2345 6789 0123 4567 8901",
+			wantErr: "too long to be a phone number",
+		},
+		{
+			name:    "unknown country code",
+			region:  "",
+			raw:     "+999 1234 5678",
+			wantErr: "invalid country code",
+		},
+		{
+			name:    "letters",
+			region:  "US",
+			raw:     "call me",
+			wantErr: `unexpected character 'c'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			n, err := phones.New(tt.region)
+			require.NoError(t, err)
+
+			got, err := n.Normalize(tt.raw)
+			if tt.wantErr != "" {
+				require.ErrorIs(t, err, phones.ErrInvalidNumber)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalizer_Canonical(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		region string
+		raw    string
+		want   string
+	}{
+		{name: "national with region", region: "US", raw: "(202) 555-0123", want: "+12025550123"},
+		{name: "international", region: "", raw: "+1 (202) 555.0123", want: "+12025550123"},
+		{name: "impossible number", region: "", raw: "+1 555-0123", want: "+15550123"},
+		{name: "unallocated but possible", region: "", raw: "+1 (102) 155-0123", want: "+11021550123"},
+		{name: "national without region", region: "", raw: "(202) 555-0123", want: "2025550123"},
+		{name: "letters", region: "US", raw: "call 555-0123", want: "5550123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			n, err := phones.New(tt.region)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, n.Canonical(tt.raw))
+		})
+	}
+}
+
+func TestNew_UnknownRegion(t *testing.T) {
+	t.Parallel()
+
+	_, err := phones.New("XX")
+	require.ErrorIs(t, err, phones.ErrUnknownRegion)
+	assert.Contains(t, err.Error(), "US")
+}
+
+func TestNormalizer_Region(t *testing.T) {
+	t.Parallel()
+
+	n, err := phones.New(" de ")
+	require.NoError(t, err)
+	assert.Equal(t, "DE", n.Region())
+}
@@ -0,0 +1,8 @@
+package templates
+
+import "errors"
+
+var (
+	ErrInvalidTemplate  = errors.New("invalid template")
+	ErrMissingVariables = errors.New("missing template variables")
+)
@@ -0,0 +1,93 @@
+package templates
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Template is a message template in Go text/template syntax, e.g.
+// "Hello {{.FirstName}}, your code is {{.Code}}".
+type Template struct {
+	tmpl   *template.Template
+	fields []string
+}
+
+// Parse parses the template text. Optional variables that are not provided
+// render as empty strings instead of "<no value>".
+func Parse(text string) (*Template, error) {
+	tmpl, err := template.New("message").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+
+	fields := []string{}
+	if tmpl.Tree != nil {
+		fields = collectFields(tmpl.Root, fields)
+	}
+	slices.Sort(fields)
+
+	return &Template{
+		tmpl:   tmpl,
+		fields: slices.Compact(fields),
+	}, nil
+}
+
+// Fields returns the sorted names of the variables required by the template,
+// i.e. referenced outside of if, with and range blocks.
+func (t *Template) Fields() []string {
+	return slices.Clone(t.fields)
+}
+
+// Missing returns the referenced variables that are absent or empty in vars.
+func (t *Template) Missing(vars map[string]string) []string {
+	missing := []string{}
+	for _, field := range t.fields {
+		if vars[field] == "" {
+			missing = append(missing, field)
+		}
+	}
+
+	return missing
+}
+
+// Execute renders the template with vars. Missing variables are reported all at once.
+func (t *Template) Execute(vars map[string]string) (string, error) {
+	if missing := t.Missing(vars); len(missing) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrMissingVariables, strings.Join(missing, ", "))
+	}
+
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+
+	return b.String(), nil
+}
+
+// collectFields walks the parse tree and collects fields that are always rendered.
+// Fields used by if, with and range are optional and are not collected.
+func collectFields(node parse.Node, fields []string) []string {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		for _, child := range n.Nodes {
+			fields = collectFields(child, fields)
+		}
+	case *parse.ActionNode:
+		fields = collectFields(n.Pipe, fields)
+	case *parse.PipeNode:
+		for _, cmd := range n.Cmds {
+			fields = collectFields(cmd, fields)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			fields = collectFields(arg, fields)
+		}
+	case *parse.FieldNode:
+		fields = append(fields, n.Ident[0])
+	}
+
+	return fields
+}
@@ -0,0 +1,68 @@
+package templates_test
+
+import (
+	"testing"
+
+	"github.com/android-sms-gateway/cli/internal/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Fields(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := templates.Parse(
+		`Hello {{.FirstName}}, your code is {{.Code}}{{if .Note}} ({{.Note}}){{end}}{{with .Extra}}{{.Ignored}}{{end}}`,
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Code", "FirstName"}, tmpl.Fields())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := templates.Parse("Hello {{.Name")
+	require.ErrorIs(t, err, templates.ErrInvalidTemplate)
+}
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := templates.Parse("Hello {{.FirstName}}, your code is {{.Code}}")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		vars    map[string]string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "all variables",
+			vars: map[string]string{"FirstName": "Ann", "Code": "1234", "Unused": "x"},
+			want: "Hello Ann, your code is 1234",
+		},
+		{
+			name:    "missing variables",
+			vars:    map[string]string{"Code": ""},
+			wantErr: "missing template variables: Code, FirstName",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, execErr := tmpl.Execute(tt.vars)
+			if tt.wantErr != "" {
+				require.ErrorIs(t, execErr, templates.ErrMissingVariables)
+				assert.EqualError(t, execErr, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, execErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
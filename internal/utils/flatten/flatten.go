@@ -0,0 +1,70 @@
+package flatten
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Flatten converts v to a map of dotted keys to leaf values using its JSON
+// representation, e.g. {"messages":{"limit_value":10}} becomes {"messages.limit_value":"10"}.
+// Array items are keyed by their index, strings are kept as is and other values are JSON encoded.
+func Flatten(v any) (map[string]string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal value: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var decoded any
+	if decErr := dec.Decode(&decoded); decErr != nil {
+		return nil, fmt.Errorf("unmarshal value: %w", decErr)
+	}
+
+	return Decoded(decoded, formatLeaf), nil
+}
+
+// Decoded flattens a value decoded from JSON, with objects as map[string]any and
+// arrays as []any, to a map of dotted keys to its leaves formatted by format.
+func Decoded(v any, format func(any) string) map[string]string {
+	result := map[string]string{}
+	walk("", v, format, result)
+
+	return result
+}
+
+func walk(prefix string, v any, format func(any) string, result map[string]string) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			walk(join(prefix, k), child, format, result)
+		}
+	case []any:
+		for i, child := range val {
+			walk(join(prefix, strconv.Itoa(i)), child, format, result)
+		}
+	default:
+		result[prefix] = format(val)
+	}
+}
+
+// formatLeaf keeps strings as is and JSON encodes other values.
+func formatLeaf(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
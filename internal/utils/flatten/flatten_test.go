@@ -0,0 +1,54 @@
+package flatten_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/android-sms-gateway/cli/internal/utils/flatten"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlatten(t *testing.T) {
+	t.Parallel()
+
+	type nested struct {
+		Limit   int      `json:"limit"`
+		Enabled bool     `json:"enabled"`
+		Mode    string   `json:"mode"`
+		Tags    []string `json:"tags"`
+		Skipped *int     `json:"skipped,omitempty"`
+		Null    *int     `json:"null"`
+	}
+
+	got, err := flatten.Flatten(map[string]any{
+		"messages": nested{Limit: 1000000, Enabled: true, Mode: "round", Tags: []string{"a", "b"}},
+		"top":      1.5,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"messages.limit":   "1000000",
+		"messages.enabled": "true",
+		"messages.mode":    "round",
+		"messages.tags.0":  "a",
+		"messages.tags.1":  "b",
+		"messages.null":    "null",
+		"top":              "1.5",
+	}, got)
+}
+
+func TestDecoded(t *testing.T) {
+	t.Parallel()
+
+	got := flatten.Decoded(
+		map[string]any{"contact": map[string]any{"phones": []any{"+12025550123", nil}}, "vip": true},
+		func(v any) string { return fmt.Sprint(v) },
+	)
+
+	assert.Equal(t, map[string]string{
+		"contact.phones.0": "+12025550123",
+		"contact.phones.1": "<nil>",
+		"vip":              "true",
+	}, got)
+}
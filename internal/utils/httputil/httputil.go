@@ -0,0 +1,47 @@
+package httputil
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Rewind returns a copy of the request with a fresh body, so it can be sent again.
+func Rewind(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.GetBody == nil {
+		return next, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("rewind request body: %w", err)
+	}
+	next.Body = body
+
+	return next, nil
+}
+
+// CanRewind reports whether the request body can be sent again.
+func CanRewind(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// DefaultRetryAfter is the pause after 429 Too Many Requests without a valid Retry-After header.
+const DefaultRetryAfter = time.Second
+
+// RetryAfter parses the Retry-After header given in seconds or as an HTTP date.
+func RetryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+
+	return 0, false
+}
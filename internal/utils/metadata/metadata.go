@@ -1,6 +1,7 @@
 package metadata
 
 import (
+	"github.com/android-sms-gateway/cli/internal/config"
 	"github.com/android-sms-gateway/cli/internal/core/output"
 	"github.com/android-sms-gateway/client-go/ca"
 	"github.com/android-sms-gateway/client-go/smsgateway"
@@ -10,6 +11,8 @@ const (
 	ClientKey   = "client"
 	CAClientKey = "caclient"
 	RendererKey = "renderer"
+	ProfileKey  = "profile"
+	EndpointKey = "endpoint"
 )
 
 func GetClient(metadata map[string]any) *smsgateway.Client {
@@ -35,3 +38,21 @@ func GetRenderer(metadata map[string]any) output.Renderer {
 	}
 	return v
 }
+
+// GetEndpoint returns the resolved API endpoint.
+func GetEndpoint(metadata map[string]any) string {
+	v, ok := metadata[EndpointKey].(string)
+	if !ok {
+		return ""
+	}
+	return v
+}
+
+// GetProfile returns the active connection profile or an empty profile if none is selected.
+func GetProfile(metadata map[string]any) config.Profile {
+	v, ok := metadata[ProfileKey].(config.Profile)
+	if !ok {
+		return config.Profile{}
+	}
+	return v
+}
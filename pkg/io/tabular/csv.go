@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"os"
 	"strings"
 )
@@ -13,7 +14,10 @@ import (
 const defaultDelimiter = ','
 
 type CSVConfig struct {
-	Path      string
+	Path string
+	// Input is read instead of the file at Path when set, e.g. os.Stdin.
+	// Writers ignore it.
+	Input     io.Reader
 	Delimiter rune
 	HasHeader bool
 }
@@ -31,61 +35,59 @@ func NewCSVReader(cfg CSVConfig) *CSVReader {
 }
 
 func (r *CSVReader) Read(ctx context.Context) ([]Record, error) {
-	if err := ctx.Err(); err != nil {
-		return nil, fmt.Errorf("read csv rows: %w", err)
-	}
-
-	f, err := os.Open(r.cfg.Path)
-	if err != nil {
-		return nil, fmt.Errorf("open csv file: %w", err)
-	}
-	defer f.Close()
-
-	reader := csv.NewReader(f)
-	reader.Comma = r.cfg.Delimiter
+	return collect(r.Records(ctx))
+}
 
-	start := 1
-	var headers []string
-	if r.cfg.HasHeader {
-		row, rdErr := reader.Read()
-		if rdErr != nil {
-			return nil, fmt.Errorf("read csv header: %w", rdErr)
+// Records streams the rows of the file.
+func (r *CSVReader) Records(ctx context.Context) iter.Seq2[Record, error] {
+	return func(yield func(Record, error) bool) {
+		if err := ctx.Err(); err != nil {
+			yield(Record{}, fmt.Errorf("read csv rows: %w", err))
+			return
 		}
-		headers = normalizeHeaders(row)
-		start = 2
-	}
 
-	records := make([]Record, 0)
-	for {
-		line, rdErr := reader.Read()
-		if rdErr != nil {
-			if errors.Is(rdErr, io.EOF) {
-				break
+		in, err := open(r.cfg.Path, r.cfg.Input)
+		if err != nil {
+			yield(Record{}, fmt.Errorf("open csv file: %w", err))
+			return
+		}
+		defer in.Close()
+
+		reader := csv.NewReader(in)
+		reader.Comma = r.cfg.Delimiter
+
+		start := 1
+		var headers []string
+		if r.cfg.HasHeader {
+			row, rdErr := reader.Read()
+			if rdErr != nil {
+				yield(Record{}, fmt.Errorf("read csv header: %w", rdErr))
+				return
 			}
-			return nil, fmt.Errorf("read csv rows: %w", rdErr)
+			headers = normalizeHeaders(row)
+			start = 2
 		}
 
-		if ctxErr := ctx.Err(); ctxErr != nil {
-			return nil, fmt.Errorf("read csv rows: %w", ctxErr)
-		}
+		for rowNumber := start; ; rowNumber++ {
+			line, rdErr := reader.Read()
+			if errors.Is(rdErr, io.EOF) {
+				return
+			}
+			if rdErr != nil {
+				yield(Record{}, fmt.Errorf("read csv rows: %w", rdErr))
+				return
+			}
 
-		values := map[string]string{}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				yield(Record{}, fmt.Errorf("read csv rows: %w", ctxErr))
+				return
+			}
 
-		for col, v := range line {
-			key := fmt.Sprintf("col_%d", col+1)
-			if len(headers) > col {
-				key = headers[col]
+			if !yield(Record{RowNumber: rowNumber, Values: recordValues(headers, line), Sparse: false}, nil) {
+				return
 			}
-			values[key] = strings.TrimSpace(v)
 		}
-
-		records = append(records, Record{
-			RowNumber: len(records) + start,
-			Values:    values,
-		})
 	}
-
-	return records, nil
 }
 
 func normalizeHeaders(src []string) []string {
@@ -115,4 +117,59 @@ func normalizeHeaders(src []string) []string {
 	return headers
 }
 
-var _ Reader = (*CSVReader)(nil)
+type CSVWriter struct {
+	cfg CSVConfig
+}
+
+// NewCSVWriter creates a writer for the file; the header is written when HasHeader is set.
+func NewCSVWriter(cfg CSVConfig) *CSVWriter {
+	if cfg.Delimiter == 0 {
+		cfg.Delimiter = defaultDelimiter
+	}
+
+	return &CSVWriter{cfg: cfg}
+}
+
+// Write writes the rows as they are produced, so they don't have to fit in memory.
+func (w *CSVWriter) Write(ctx context.Context, header []string, rows iter.Seq[[]string]) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("write csv rows: %w", err)
+	}
+
+	f, err := os.OpenFile(w.cfg.Path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, fileMode)
+	if err != nil {
+		return fmt.Errorf("create csv file: %w", err)
+	}
+	defer f.Close()
+
+	writer := csv.NewWriter(f)
+	writer.Comma = w.cfg.Delimiter
+
+	if w.cfg.HasHeader {
+		if wrErr := writer.Write(header); wrErr != nil {
+			return fmt.Errorf("write csv header: %w", wrErr)
+		}
+	}
+	for row := range rows {
+		if wrErr := writer.Write(row); wrErr != nil {
+			return fmt.Errorf("write csv rows: %w", wrErr)
+		}
+	}
+
+	writer.Flush()
+	if wrErr := writer.Error(); wrErr != nil {
+		return fmt.Errorf("write csv rows: %w", wrErr)
+	}
+
+	if closeErr := f.Close(); closeErr != nil {
+		return fmt.Errorf("close csv file: %w", closeErr)
+	}
+
+	return nil
+}
+
+var (
+	_ Reader       = (*CSVReader)(nil)
+	_ StreamReader = (*CSVReader)(nil)
+	_ Writer       = (*CSVWriter)(nil)
+)
@@ -4,6 +4,8 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/android-sms-gateway/cli/pkg/io/tabular"
@@ -43,3 +45,85 @@ func TestCSVReader_Read_WithoutHeader(t *testing.T) {
 	assert.Equal(t, "+12025550123", records[0].Values["col_1"])
 	assert.Equal(t, "Hello", records[0].Values["col_2"])
 }
+
+func TestCSVWriter_Write(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "output.csv")
+
+	writer := tabular.NewCSVWriter(tabular.CSVConfig{Path: path, Delimiter: ';', HasHeader: true})
+	require.NoError(t, writer.Write(
+		context.Background(),
+		[]string{"Phone", "Message"},
+		slices.Values([][]string{{"+12025550123", "Hello; world"}}),
+	))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Phone;Message\n+12025550123;\"Hello; world\"\n", string(b))
+
+	reader := tabular.NewCSVReader(tabular.CSVConfig{Path: path, Delimiter: ';', HasHeader: true})
+	records, err := reader.Read(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "Hello; world", records[0].Values["Message"])
+}
+
+func TestCSVReader_Read_Input(t *testing.T) {
+	t.Parallel()
+
+	reader := tabular.NewCSVReader(tabular.CSVConfig{
+		Input:     strings.NewReader("Phone,Message\n+12025550123,Hello\n"),
+		HasHeader: true,
+	})
+	records, err := reader.Read(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "+12025550123", records[0].Values["Phone"])
+}
+
+func TestCSVReader_Records(t *testing.T) {
+	t.Parallel()
+
+	reader := tabular.NewCSVReader(tabular.CSVConfig{
+		Input:     strings.NewReader("Phone,Message\n+12025550123,Hello\n+12025550124,Hi\n+12025550125\n"),
+		Delimiter: ',',
+		HasHeader: true,
+	})
+
+	var phones []string
+	for record, err := range reader.Records(context.Background()) {
+		require.NoError(t, err)
+		phones = append(phones, record.Values["Phone"])
+		if len(phones) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"+12025550123", "+12025550124"}, phones)
+}
+
+func TestCSVReader_Records_InvalidRow(t *testing.T) {
+	t.Parallel()
+
+	reader := tabular.NewCSVReader(tabular.CSVConfig{
+		Input:     strings.NewReader("Phone,Message\n+12025550123,Hello\n+12025550124\n"),
+		Delimiter: ',',
+		HasHeader: true,
+	})
+
+	var (
+		phones  []string
+		readErr error
+	)
+	for record, err := range reader.Records(context.Background()) {
+		if err != nil {
+			readErr = err
+			continue
+		}
+		phones = append(phones, record.Values["Phone"])
+	}
+	assert.Equal(t, []string{"+12025550123"}, phones)
+	require.Error(t, readErr)
+	assert.Contains(t, readErr.Error(), "read csv rows")
+}
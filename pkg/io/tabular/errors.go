@@ -0,0 +1,7 @@
+package tabular
+
+import "errors"
+
+var (
+	ErrInvalidInput = errors.New("invalid input")
+)
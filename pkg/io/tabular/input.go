@@ -0,0 +1,20 @@
+package tabular
+
+import (
+	"io"
+	"os"
+)
+
+// open returns the input to read: r when set, the file at path otherwise.
+func open(path string, r io.Reader) (io.ReadCloser, error) {
+	if r != nil {
+		return io.NopCloser(r), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // wrapped by the callers
+	}
+
+	return f, nil
+}
@@ -0,0 +1,184 @@
+package tabular
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+
+	"github.com/android-sms-gateway/cli/internal/utils/flatten"
+)
+
+// JSONConfig configures the JSON and NDJSON readers.
+type JSONConfig struct {
+	Path string
+	// Input is read instead of the file at Path when set, e.g. os.Stdin.
+	Input io.Reader
+}
+
+// JSONReader reads a JSON array of objects, one record per object.
+// Nested objects and arrays are flattened to dotted column names,
+// e.g. {"contact":{"mobile":"+1..."}} has the column "contact.mobile".
+type JSONReader struct {
+	cfg JSONConfig
+}
+
+func NewJSONReader(cfg JSONConfig) *JSONReader {
+	return &JSONReader{cfg: cfg}
+}
+
+func (r *JSONReader) Read(ctx context.Context) ([]Record, error) {
+	return collect(r.Records(ctx))
+}
+
+// Records streams the objects of the array.
+func (r *JSONReader) Records(ctx context.Context) iter.Seq2[Record, error] {
+	return func(yield func(Record, error) bool) {
+		if err := ctx.Err(); err != nil {
+			yield(Record{}, fmt.Errorf("read json rows: %w", err))
+			return
+		}
+
+		in, err := open(r.cfg.Path, r.cfg.Input)
+		if err != nil {
+			yield(Record{}, fmt.Errorf("open json file: %w", err))
+			return
+		}
+		defer in.Close()
+
+		dec := json.NewDecoder(in)
+		if tok, tokErr := dec.Token(); tokErr != nil || tok != json.Delim('[') {
+			yield(Record{}, fmt.Errorf("read json rows: %w: expected an array of objects", ErrInvalidInput))
+			return
+		}
+
+		for rowNumber := 1; dec.More(); rowNumber++ {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				yield(Record{}, fmt.Errorf("read json rows: %w", ctxErr))
+				return
+			}
+
+			var item json.RawMessage
+			if decErr := dec.Decode(&item); decErr != nil {
+				yield(Record{}, fmt.Errorf("read json row %d: %w", rowNumber, decErr))
+				return
+			}
+
+			values, flatErr := flattenObject(item)
+			if flatErr != nil {
+				yield(Record{}, fmt.Errorf("read json row %d: %w", rowNumber, flatErr))
+				return
+			}
+
+			if !yield(Record{RowNumber: rowNumber, Values: values, Sparse: true}, nil) {
+				return
+			}
+		}
+	}
+}
+
+// NDJSONReader reads newline-delimited JSON, one record per object line.
+// Blank lines are skipped, the row number is the line number.
+type NDJSONReader struct {
+	cfg JSONConfig
+}
+
+func NewNDJSONReader(cfg JSONConfig) *NDJSONReader {
+	return &NDJSONReader{cfg: cfg}
+}
+
+func (r *NDJSONReader) Read(ctx context.Context) ([]Record, error) {
+	return collect(r.Records(ctx))
+}
+
+// Records streams the object lines.
+func (r *NDJSONReader) Records(ctx context.Context) iter.Seq2[Record, error] {
+	return func(yield func(Record, error) bool) {
+		if err := ctx.Err(); err != nil {
+			yield(Record{}, fmt.Errorf("read ndjson rows: %w", err))
+			return
+		}
+
+		in, err := open(r.cfg.Path, r.cfg.Input)
+		if err != nil {
+			yield(Record{}, fmt.Errorf("open ndjson file: %w", err))
+			return
+		}
+		defer in.Close()
+
+		reader := bufio.NewReader(in)
+		for lineNumber := 1; ; lineNumber++ {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				yield(Record{}, fmt.Errorf("read ndjson rows: %w", ctxErr))
+				return
+			}
+
+			line, rdErr := reader.ReadBytes('\n')
+			if rdErr != nil && !errors.Is(rdErr, io.EOF) {
+				yield(Record{}, fmt.Errorf("read ndjson rows: %w", rdErr))
+				return
+			}
+
+			if len(bytes.TrimSpace(line)) > 0 {
+				values, flatErr := flattenObject(line)
+				if flatErr != nil {
+					yield(Record{}, fmt.Errorf("read ndjson line %d: %w", lineNumber, flatErr))
+					return
+				}
+				if !yield(Record{RowNumber: lineNumber, Values: values, Sparse: true}, nil) {
+					return
+				}
+			}
+
+			if rdErr != nil {
+				return
+			}
+		}
+	}
+}
+
+// flattenObject decodes a JSON object to values keyed by dotted paths. Array
+// items are keyed by their index, null is an empty value.
+func flattenObject(b []byte) (map[string]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("decode object: %w", err)
+	}
+
+	object, ok := decoded.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected an object", ErrInvalidInput)
+	}
+
+	return flatten.Decoded(object, formatValue), nil
+}
+
+// formatValue trims strings and reads null as an empty value.
+func formatValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		return strings.TrimSpace(val)
+	case json.Number:
+		return val.String()
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		return ""
+	}
+}
+
+var (
+	_ Reader       = (*JSONReader)(nil)
+	_ StreamReader = (*JSONReader)(nil)
+	_ Reader       = (*NDJSONReader)(nil)
+	_ StreamReader = (*NDJSONReader)(nil)
+)
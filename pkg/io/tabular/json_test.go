@@ -0,0 +1,123 @@
+package tabular_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/android-sms-gateway/cli/pkg/io/tabular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONReader_Read(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "input.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"contact": {"mobile": " +12025550123 ", "tags": ["vip", 7]}, "body": "Hello", "priority": 100, "report": true},
+		{"contact": {"mobile": "+12025550124", "email": null}, "body": "Hi"}
+	]`), 0o600))
+
+	reader := tabular.NewJSONReader(tabular.JSONConfig{Path: path})
+	records, err := reader.Read(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, 1, records[0].RowNumber)
+	assert.Equal(t, map[string]string{
+		"contact.mobile": "+12025550123",
+		"contact.tags.0": "vip",
+		"contact.tags.1": "7",
+		"body":           "Hello",
+		"priority":       "100",
+		"report":         "true",
+	}, records[0].Values)
+
+	assert.Equal(t, 2, records[1].RowNumber)
+	assert.Equal(t, map[string]string{
+		"contact.mobile": "+12025550124",
+		"contact.email":  "",
+		"body":           "Hi",
+	}, records[1].Values)
+}
+
+func TestJSONReader_Read_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		expectedErr string
+	}{
+		{name: "not an array", input: `{"phone": "+12025550123"}`, expectedErr: "expected an array of objects"},
+		{name: "not an object", input: `[{"phone": "+12025550123"}, "+12025550124"]`, expectedErr: "row 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reader := tabular.NewJSONReader(tabular.JSONConfig{Input: strings.NewReader(tt.input)})
+			_, err := reader.Read(context.Background())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestNDJSONReader_Read(t *testing.T) {
+	t.Parallel()
+
+	input := "{\"contact\":{\"mobile\":\"+12025550123\"},\"body\":\"Hello\"}\n" +
+		"\n" +
+		"{\"contact\":{\"mobile\":\"+12025550124\"},\"body\":\"Hi\"}"
+
+	reader := tabular.NewNDJSONReader(tabular.JSONConfig{Input: strings.NewReader(input)})
+	records, err := reader.Read(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, 1, records[0].RowNumber)
+	assert.Equal(t, "+12025550123", records[0].Values["contact.mobile"])
+	assert.Equal(t, 3, records[1].RowNumber)
+	assert.Equal(t, "Hi", records[1].Values["body"])
+}
+
+func TestNDJSONReader_Read_InvalidLine(t *testing.T) {
+	t.Parallel()
+
+	input := "{\"phone\":\"+12025550123\"}\n[1, 2]\n"
+
+	reader := tabular.NewNDJSONReader(tabular.JSONConfig{Input: strings.NewReader(input)})
+	_, err := reader.Read(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read ndjson line 2")
+	require.ErrorIs(t, err, tabular.ErrInvalidInput)
+}
+
+func TestJSONReader_Records_InvalidItem(t *testing.T) {
+	t.Parallel()
+
+	reader := tabular.NewJSONReader(tabular.JSONConfig{
+		Input: strings.NewReader(`[{"phone": "+12025550123"}, "oops", {"phone": "+12025550124"}]`),
+	})
+
+	var (
+		phones  []string
+		readErr error
+	)
+	for record, err := range reader.Records(context.Background()) {
+		if err != nil {
+			readErr = err
+			continue
+		}
+		phones = append(phones, record.Values["phone"])
+	}
+	assert.Equal(t, []string{"+12025550123"}, phones)
+	require.Error(t, readErr)
+	assert.Contains(t, readErr.Error(), "read json row 2")
+}
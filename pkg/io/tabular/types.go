@@ -1,14 +1,63 @@
 package tabular
 
-import "context"
+import (
+	"context"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+// fileMode is the permission of written files, they usually hold phone numbers.
+const fileMode = 0o600
 
 // Record is a normalized row from a tabular input source.
 type Record struct {
 	RowNumber int
 	Values    map[string]string
+	// Sparse records may omit columns, e.g. JSON objects without optional keys,
+	// so a missing column is an empty value rather than a mapping error.
+	Sparse bool
 }
 
 // Reader defines the contract for reading tabular records.
 type Reader interface {
 	Read(ctx context.Context) ([]Record, error)
 }
+
+// StreamReader reads records one at a time, so the input doesn't have to fit in memory.
+// The iteration stops after the first error.
+type StreamReader interface {
+	Records(ctx context.Context) iter.Seq2[Record, error]
+}
+
+// Writer defines the contract for writing tabular rows.
+type Writer interface {
+	Write(ctx context.Context, header []string, rows iter.Seq[[]string]) error
+}
+
+// collect returns all records of the stream.
+func collect(records iter.Seq2[Record, error]) ([]Record, error) {
+	result := make([]Record, 0)
+	for record, err := range records {
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, record)
+	}
+
+	return result, nil
+}
+
+// recordValues keys the line values by the headers, columns without a header are named col_N.
+func recordValues(headers, line []string) map[string]string {
+	values := make(map[string]string, len(line))
+	for col, v := range line {
+		key := "col_" + strconv.Itoa(col+1)
+		if len(headers) > col {
+			key = headers[col]
+		}
+		values[key] = strings.TrimSpace(v)
+	}
+
+	return values
+}
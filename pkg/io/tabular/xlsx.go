@@ -3,13 +3,19 @@ package tabular
 import (
 	"context"
 	"fmt"
+	"io"
+	"iter"
+	"os"
 	"strings"
 
 	"github.com/xuri/excelize/v2"
 )
 
 type XLSXConfig struct {
-	Path      string
+	Path string
+	// Input is read instead of the file at Path when set, e.g. os.Stdin.
+	// Writers ignore it.
+	Input     io.Reader
 	Sheet     string
 	HasHeader bool
 }
@@ -23,80 +29,189 @@ func NewXLSXReader(cfg XLSXConfig) *XLSXReader {
 }
 
 func (r *XLSXReader) Read(ctx context.Context) ([]Record, error) {
-	if err := ctx.Err(); err != nil {
-		return nil, fmt.Errorf("read xlsx rows: %w", err)
-	}
+	return collect(r.Records(ctx))
+}
 
-	f, err := excelize.OpenFile(r.cfg.Path)
-	if err != nil {
-		return nil, fmt.Errorf("open xlsx file: %w", err)
-	}
-	defer f.Close()
+// Records streams the rows of the sheet.
+func (r *XLSXReader) Records(ctx context.Context) iter.Seq2[Record, error] {
+	return func(yield func(Record, error) bool) {
+		if err := ctx.Err(); err != nil {
+			yield(Record{}, fmt.Errorf("read xlsx rows: %w", err))
+			return
+		}
 
-	sheet := strings.TrimSpace(r.cfg.Sheet)
-	if sheet == "" {
-		sheetList := f.GetSheetList()
-		if len(sheetList) == 0 {
-			return []Record{}, nil
+		in, err := open(r.cfg.Path, r.cfg.Input)
+		if err != nil {
+			yield(Record{}, fmt.Errorf("open xlsx file: %w", err))
+			return
 		}
-		sheet = sheetList[0]
-	}
+		defer in.Close()
 
-	rows, err := f.Rows(sheet)
-	if err != nil {
-		return nil, fmt.Errorf("read xlsx rows: %w", err)
+		f, err := excelize.OpenReader(in)
+		if err != nil {
+			yield(Record{}, fmt.Errorf("open xlsx file: %w", err))
+			return
+		}
+		defer f.Close()
+
+		sheet := strings.TrimSpace(r.cfg.Sheet)
+		if sheet == "" {
+			sheetList := f.GetSheetList()
+			if len(sheetList) == 0 {
+				return
+			}
+			sheet = sheetList[0]
+		}
+
+		rows, err := f.Rows(sheet)
+		if err != nil {
+			yield(Record{}, fmt.Errorf("read xlsx rows: %w", err))
+			return
+		}
+		defer rows.Close()
+
+		r.yieldRows(ctx, rows, yield)
 	}
-	defer rows.Close()
+}
 
+func (r *XLSXReader) yieldRows(ctx context.Context, rows *excelize.Rows, yield func(Record, error) bool) {
 	start := 1
 	var headers []string
 	if r.cfg.HasHeader {
 		if !rows.Next() {
-			return []Record{}, nil
+			return
 		}
 
 		line, colErr := rows.Columns()
 		if colErr != nil {
-			return nil, fmt.Errorf("read xlsx header: %w", colErr)
+			yield(Record{}, fmt.Errorf("read xlsx header: %w", colErr))
+			return
 		}
 
 		headers = normalizeHeaders(line)
 		start = 2
 	}
 
-	return r.readRecords(ctx, headers, start, rows)
-}
-
-func (r *XLSXReader) readRecords(
-	ctx context.Context,
-	headers []string,
-	start int,
-	rows *excelize.Rows,
-) ([]Record, error) {
-	records := make([]Record, 0)
-	for rows.Next() {
+	for rowNumber := start; rows.Next(); rowNumber++ {
 		if ctxErr := ctx.Err(); ctxErr != nil {
-			return nil, fmt.Errorf("read xlsx rows: %w", ctxErr)
+			yield(Record{}, fmt.Errorf("read xlsx rows: %w", ctxErr))
+			return
 		}
 
 		line, rdErr := rows.Columns()
 		if rdErr != nil {
-			return nil, fmt.Errorf("read xlsx row: %w", rdErr)
+			yield(Record{}, fmt.Errorf("read xlsx row: %w", rdErr))
+			return
+		}
+
+		if !yield(Record{RowNumber: rowNumber, Values: recordValues(headers, line), Sparse: false}, nil) {
+			return
+		}
+	}
+
+	if err := rows.Error(); err != nil {
+		yield(Record{}, fmt.Errorf("read xlsx rows: %w", err))
+	}
+}
+
+const defaultSheet = "Sheet1"
+
+type XLSXWriter struct {
+	cfg XLSXConfig
+}
+
+// NewXLSXWriter creates a writer for the file; the header is written when HasHeader is set.
+// The sheet is named Sheet1 unless configured.
+func NewXLSXWriter(cfg XLSXConfig) *XLSXWriter {
+	if strings.TrimSpace(cfg.Sheet) == "" {
+		cfg.Sheet = defaultSheet
+	}
+
+	return &XLSXWriter{cfg: cfg}
+}
+
+// Write writes the rows as they are produced with a stream writer.
+func (w *XLSXWriter) Write(ctx context.Context, header []string, rows iter.Seq[[]string]) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("write xlsx rows: %w", err)
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if w.cfg.Sheet != defaultSheet {
+		if err := f.SetSheetName(defaultSheet, w.cfg.Sheet); err != nil {
+			return fmt.Errorf("create xlsx sheet: %w", err)
 		}
+	}
+
+	sw, err := f.NewStreamWriter(w.cfg.Sheet)
+	if err != nil {
+		return fmt.Errorf("create xlsx sheet: %w", err)
+	}
 
-		values := map[string]string{}
-		for col, v := range line {
-			key := fmt.Sprintf("col_%d", col+1)
-			if len(headers) > col {
-				key = headers[col]
+	lines := rows
+	if w.cfg.HasHeader {
+		lines = func(yield func([]string) bool) {
+			if !yield(header) {
+				return
 			}
-			values[key] = strings.TrimSpace(v)
+			rows(yield)
 		}
+	}
+
+	rowNumber := 0
+	for line := range lines {
+		rowNumber++
+		if rowErr := writeXLSXRow(sw, rowNumber, line); rowErr != nil {
+			return rowErr
+		}
+	}
 
-		records = append(records, Record{RowNumber: len(records) + start, Values: values})
+	if flushErr := sw.Flush(); flushErr != nil {
+		return fmt.Errorf("write xlsx rows: %w", flushErr)
+	}
+	return saveXLSX(f, w.cfg.Path)
+}
+
+// saveXLSX writes the workbook to a private file, excelize.File.SaveAs would
+// create it readable by everyone.
+func saveXLSX(f *excelize.File, path string) error {
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, fileMode)
+	if err != nil {
+		return fmt.Errorf("save xlsx file: %w", err)
+	}
+	defer out.Close()
+
+	if _, writeErr := f.WriteTo(out); writeErr != nil {
+		return fmt.Errorf("save xlsx file: %w", writeErr)
+	}
+	if closeErr := out.Close(); closeErr != nil {
+		return fmt.Errorf("save xlsx file: %w", closeErr)
+	}
+
+	return nil
+}
+
+func writeXLSXRow(sw *excelize.StreamWriter, rowNumber int, line []string) error {
+	cells := make([]any, len(line))
+	for i, v := range line {
+		cells[i] = v
+	}
+
+	cell, err := excelize.CoordinatesToCellName(1, rowNumber)
+	if err != nil {
+		return fmt.Errorf("write xlsx row: %w", err)
+	}
+	if rowErr := sw.SetRow(cell, cells); rowErr != nil {
+		return fmt.Errorf("write xlsx row: %w", rowErr)
 	}
 
-	return records, nil
+	return nil
 }
 
-var _ Reader = (*XLSXReader)(nil)
+var (
+	_ Reader       = (*XLSXReader)(nil)
+	_ StreamReader = (*XLSXReader)(nil)
+	_ Writer       = (*XLSXWriter)(nil)
+)
@@ -3,6 +3,7 @@ package tabular_test
 import (
 	"context"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/android-sms-gateway/cli/pkg/io/tabular"
@@ -64,3 +65,26 @@ func TestXLSXReader_Read_WithoutHeader(t *testing.T) {
 	assert.Equal(t, "+12025550123", records[0].Values["col_1"])
 	assert.Equal(t, "Hello", records[0].Values["col_2"])
 }
+
+func TestXLSXWriter_Write(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "output.xlsx")
+
+	writer := tabular.NewXLSXWriter(tabular.XLSXConfig{Path: path, Sheet: "Results", HasHeader: true})
+	require.NoError(t, writer.Write(
+		context.Background(),
+		[]string{"Phone", "Message"},
+		slices.Values([][]string{{"+12025550123", "Hello"}, {"+12025550124", "Bye"}}),
+	))
+
+	reader := tabular.NewXLSXReader(tabular.XLSXConfig{Path: path, Sheet: "Results", HasHeader: true})
+	records, err := reader.Read(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, 3, records[1].RowNumber)
+	assert.Equal(t, "+12025550124", records[1].Values["Phone"])
+	assert.Equal(t, "Bye", records[1].Values["Message"])
+}
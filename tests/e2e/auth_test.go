@@ -0,0 +1,93 @@
+package e2e
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"e2e/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthPrecedence(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	var (
+		mu            sync.Mutex
+		authorization string
+	)
+	mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		authorization = r.Header.Get("Authorization")
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("[]"))
+	})
+	defer mockServer.Close()
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	run := func(t *testing.T, env []string, args ...string) {
+		t.Helper()
+
+		var stderr bytes.Buffer
+		cmd := exec.Command(binPath, append([]string{"--config", configFile}, args...)...)
+		// only the credentials of the test case are passed
+		for _, item := range os.Environ() {
+			if !strings.HasPrefix(item, "ASG_") {
+				cmd.Env = append(cmd.Env, item)
+			}
+		}
+		cmd.Env = append(cmd.Env, env...)
+		cmd.Stderr = &stderr
+		require.NoError(t, cmd.Run(), "stderr: %s", stderr.String())
+	}
+
+	run(t, nil, "config", "set", "--endpoint", mockServer.URL, "--token", "profile-token", "main")
+
+	tests := []struct {
+		name string
+		env  []string
+		args []string
+		want string
+	}{
+		{
+			name: "profile token",
+			want: "Bearer profile-token",
+		},
+		{
+			name: "env credentials over profile token",
+			env:  []string{"ASG_USERNAME=user", "ASG_PASSWORD=pass"},
+			want: "Basic dXNlcjpwYXNz",
+		},
+		{
+			name: "flag credentials over env token",
+			env:  []string{"ASG_TOKEN=env-token"},
+			args: []string{"-u", "user", "-p", "pass"},
+			want: "Basic dXNlcjpwYXNz",
+		},
+		{
+			name: "env token over profile token",
+			env:  []string{"ASG_TOKEN=env-token"},
+			want: "Bearer env-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run(t, tt.env, append(append([]string{"--profile", "main"}, tt.args...), "devices", "list")...)
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, tt.want, authorization)
+		})
+	}
+}
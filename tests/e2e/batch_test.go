@@ -0,0 +1,184 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"e2e/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchSendStdin(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	var (
+		mu     sync.Mutex
+		phones []string
+	)
+	mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID           string   `json:"id"`
+			PhoneNumbers []string `json:"phoneNumbers"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		phones = append(phones, req.PhoneNumbers...)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": req.ID, "state": "Pending"})
+	})
+	defer mockServer.Close()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(binPath,
+		"batch", "send",
+		"--input-format", "ndjson",
+		"--map", "phone=contact.mobile,text=body",
+		"-",
+	)
+	cmd.Env = append([]string{}, os.Environ()...)
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+	cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+	cmd.Stdin = strings.NewReader(
+		"{\"contact\":{\"mobile\":\"+12025550123\"},\"body\":\"Hello\"}\n" +
+			"{\"contact\":{\"mobile\":\"+12025550124\"},\"body\":\"Hi\"}\n",
+	)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	require.NoError(t, err, "stderr: %s", stderr.String())
+	assert.Contains(t, stderr.String(), "total=2 enqueued=2 failed=0")
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.ElementsMatch(t, []string{"+12025550123", "+12025550124"}, phones)
+}
+
+func TestBatchSendDevices(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	var (
+		mu      sync.Mutex
+		devices = map[string]int{}
+	)
+	mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID        string `json:"id"`
+			DeviceID  string `json:"deviceId"`
+			SimNumber int    `json:"simNumber"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		devices[fmt.Sprintf("%s/%d", req.DeviceID, req.SimNumber)]++
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": req.ID, "state": "Pending"})
+	})
+	defer mockServer.Close()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(binPath,
+		"batch", "send",
+		"--input-format", "ndjson",
+		"--map", "phone=phone,text=body",
+		"--devices", "d1,d2",
+		"--sims", "1,2",
+		"--device-concurrency", "1",
+		"-",
+	)
+	cmd.Env = append([]string{}, os.Environ()...)
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+	cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+	cmd.Stdin = strings.NewReader(
+		"{\"phone\":\"+12025550121\",\"body\":\"Hello\"}\n" +
+			"{\"phone\":\"+12025550122\",\"body\":\"Hello\"}\n" +
+			"{\"phone\":\"+12025550123\",\"body\":\"Hello\"}\n" +
+			"{\"phone\":\"+12025550124\",\"body\":\"Hello\"}\n" +
+			"{\"phone\":\"+12025550125\",\"body\":\"Hello\"}\n",
+	)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	require.NoError(t, err, "stderr: %s", stderr.String())
+	assert.Contains(t, stderr.String(), "total=5 enqueued=5 failed=0")
+	assert.Contains(t, stderr.String(), "  d1 SIM 1: enqueued=2 failed=0\n")
+	assert.Contains(t, stderr.String(), "  d2 SIM 2: enqueued=1 failed=0\n")
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, map[string]int{"d1/1": 2, "d2/1": 1, "d1/2": 1, "d2/2": 1}, devices)
+}
+
+func TestBatchSendInterrupted(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(binPath,
+		"batch", "send",
+		"--input-format", "ndjson",
+		"--map", "phone=phone,text=body",
+		"--concurrency", "1",
+		"-",
+	)
+
+	var once sync.Once
+	mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID string `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// the batch is interrupted while the first message is in flight
+		once.Do(func() {
+			cmd.Process.Signal(os.Interrupt)
+			time.Sleep(100 * time.Millisecond)
+		})
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": req.ID, "state": "Pending"})
+	})
+	defer mockServer.Close()
+
+	cmd.Env = append([]string{}, os.Environ()...)
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+	cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+	cmd.Stdin = strings.NewReader(
+		"{\"phone\":\"+12025550121\",\"body\":\"Hello\"}\n" +
+			"{\"phone\":\"+12025550122\",\"body\":\"Hello\"}\n" +
+			"{\"phone\":\"+12025550123\",\"body\":\"Hello\"}\n",
+	)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// the message in flight is cancelled and fails, the rest are skipped
+	require.Error(t, cmd.Run())
+	assert.Contains(t, stderr.String(), "total=3 enqueued=0 failed=1 skipped=2")
+}
@@ -0,0 +1,163 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"testing"
+
+	"e2e/testutils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const devicesResponse = `[
+	{
+		"id": "dev-001",
+		"name": "Pixel 7",
+		"createdAt": "2024-01-01T00:00:00Z",
+		"updatedAt": "2024-01-02T00:00:00Z",
+		"lastSeen": "2024-01-03T00:00:00Z"
+	},
+	{
+		"id": "dev-002",
+		"name": "Galaxy S23",
+		"createdAt": "2024-02-01T00:00:00Z",
+		"updatedAt": "2024-02-02T00:00:00Z",
+		"lastSeen": "2024-02-03T00:00:00Z"
+	}
+]`
+
+func TestDevicesList(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	tests := []struct {
+		name     string
+		format   string
+		response string
+		contains []string
+	}{
+		{
+			name:     "empty list",
+			format:   "text",
+			response: "[]",
+			contains: []string{"Empty result"},
+		},
+		{
+			name:     "text",
+			format:   "text",
+			response: devicesResponse,
+			contains: []string{"ID: dev-001", "Name: Pixel 7", "---", "ID: dev-002", "Last Seen: "},
+		},
+		{
+			name:     "table",
+			format:   "table",
+			response: devicesResponse,
+			contains: []string{"ID", "NAME", "LAST SEEN", "CREATED AT", "dev-001", "Galaxy S23"},
+		},
+		{
+			name:     "json",
+			format:   "json",
+			response: devicesResponse,
+			contains: []string{`"id": "dev-001"`, `"name": "Galaxy S23"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/devices", r.URL.Path)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.response))
+			})
+			defer mockServer.Close()
+
+			var stdout, stderr bytes.Buffer
+			cmd := exec.Command(binPath, "--format", tt.format, "devices", "list")
+			cmd.Env = append([]string{}, os.Environ()...)
+			cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+			cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			err := cmd.Run()
+			assert.NoError(t, err, "stderr: %s", stderr.String())
+
+			for _, s := range tt.contains {
+				assert.Contains(t, stdout.String(), s)
+			}
+
+			if tt.format == "json" {
+				var devices []map[string]interface{}
+				assert.NoError(t, json.Unmarshal(stdout.Bytes(), &devices))
+				assert.Len(t, devices, 2)
+			}
+		})
+	}
+}
+
+func TestDevicesRemove(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	tests := []struct {
+		name        string
+		args        []string
+		setupStatus int
+		expectError bool
+		expectedErr string
+	}{
+		{
+			name:        "remove existing device",
+			args:        []string{"dev-001"},
+			setupStatus: http.StatusNoContent,
+		},
+		{
+			name:        "remove non-existing device",
+			args:        []string{"dev-404"},
+			setupStatus: http.StatusNotFound,
+			expectError: true,
+		},
+		{
+			name:        "empty ID",
+			setupStatus: http.StatusNoContent,
+			expectError: true,
+			expectedErr: "ID is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodDelete, r.Method)
+				assert.Equal(t, "/devices/"+tt.args[0], r.URL.Path)
+
+				w.WriteHeader(tt.setupStatus)
+			})
+			defer mockServer.Close()
+
+			var stdout, stderr bytes.Buffer
+			cmd := exec.Command(binPath, append([]string{"devices", "remove"}, tt.args...)...)
+			cmd.Env = append([]string{}, os.Environ()...)
+			cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+			cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			err := cmd.Run()
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, stderr.String(), tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err, "stderr: %s", stderr.String())
+			assert.Contains(t, stdout.String(), "Success")
+		})
+	}
+}
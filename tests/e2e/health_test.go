@@ -0,0 +1,180 @@
+package e2e
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"e2e/testutils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealth(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	tests := []struct {
+		name         string
+		format       string
+		status       string
+		httpStatus   int
+		expectedCode int
+		contains     []string
+	}{
+		{
+			name:         "pass",
+			format:       "text",
+			status:       "pass",
+			httpStatus:   http.StatusOK,
+			expectedCode: 0,
+			contains:     []string{"Status: pass", "Version: 1.2.3", "db:ping", "memory:usage\twarn\t80 %"},
+		},
+		{
+			name:         "warn",
+			format:       "table",
+			status:       "warn",
+			httpStatus:   http.StatusOK,
+			expectedCode: 1,
+			contains:     []string{"NAME", "STATUS", "db:ping", "80 %"},
+		},
+		{
+			name:         "fail",
+			format:       "json",
+			status:       "fail",
+			httpStatus:   http.StatusOK,
+			expectedCode: 2,
+			contains:     []string{`"status": "fail"`, `"db:ping"`},
+		},
+		{
+			name:         "fail with service unavailable",
+			format:       "text",
+			status:       "fail",
+			httpStatus:   http.StatusServiceUnavailable,
+			expectedCode: 2,
+			contains:     []string{"Status: fail"},
+		},
+		{
+			name:         "unknown status",
+			format:       "text",
+			status:       "degraded",
+			httpStatus:   http.StatusOK,
+			expectedCode: 3,
+		},
+		{
+			name:         "server error",
+			format:       "text",
+			httpStatus:   http.StatusInternalServerError,
+			expectedCode: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/health", r.URL.Path)
+				assert.Empty(t, r.Header.Get("Authorization"))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.httpStatus)
+				fmt.Fprintf(w, `{
+					"status": %q,
+					"version": "1.2.3",
+					"releaseId": 42,
+					"checks": {
+						"db:ping": {"status": "pass", "observedValue": 1, "description": "Database ping"},
+						"memory:usage": {"status": "warn", "observedValue": 80, "observedUnit": "%%"}
+					}
+				}`, tt.status)
+			})
+			defer mockServer.Close()
+
+			var stdout, stderr bytes.Buffer
+			cmd := exec.Command(binPath, "--format", tt.format, "health")
+			// the probe works without credentials
+			for _, item := range os.Environ() {
+				if !strings.HasPrefix(item, "ASG_") {
+					cmd.Env = append(cmd.Env, item)
+				}
+			}
+			cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			err := cmd.Run()
+			if tt.expectedCode == 0 {
+				assert.NoError(t, err, "stderr: %s", stderr.String())
+			} else {
+				var exitErr *exec.ExitError
+				assert.True(t, errors.As(err, &exitErr))
+				assert.Equal(t, tt.expectedCode, exitErr.ExitCode(), "stderr: %s", stderr.String())
+			}
+
+			for _, s := range tt.contains {
+				assert.Contains(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+func TestHealthSetupFailure(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	tests := []struct {
+		name     string
+		args     []string
+		endpoint string
+		contains string
+	}{
+		{
+			name:     "malformed endpoint",
+			args:     []string{"health"},
+			endpoint: "localhost:3000",
+			contains: `invalid endpoint "localhost:3000"`,
+		},
+		{
+			name:     "unknown format",
+			args:     []string{"--format", "xml", "health"},
+			endpoint: "http://localhost:3000",
+			contains: "failed to create renderer",
+		},
+		{
+			name:     "unknown profile",
+			args:     []string{"--profile", "missing", "health"},
+			endpoint: "http://localhost:3000",
+			contains: "failed to select profile",
+		},
+		{
+			name:     "unknown flag",
+			args:     []string{"health", "--verbose"},
+			endpoint: "http://localhost:3000",
+			contains: "flag provided but not defined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			cmd := exec.Command(binPath, append([]string{"--config", filepath.Join(t.TempDir(), "config.yml")}, tt.args...)...)
+			for _, item := range os.Environ() {
+				if !strings.HasPrefix(item, "ASG_") {
+					cmd.Env = append(cmd.Env, item)
+				}
+			}
+			cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", tt.endpoint))
+			cmd.Stderr = &stderr
+
+			err := cmd.Run()
+			var exitErr *exec.ExitError
+			assert.True(t, errors.As(err, &exitErr))
+			assert.Equal(t, 3, exitErr.ExitCode(), "stderr: %s", stderr.String())
+			assert.Contains(t, stderr.String(), tt.contains)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"e2e/testutils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInboxExport(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	from := time.Date(2025, 1, 10, 11, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("export", func(t *testing.T) {
+		mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/inbox/export", r.URL.Path)
+
+			var req map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "device-1", req["deviceId"])
+			assert.Equal(t, from.Format(time.RFC3339), req["since"])
+			assert.Equal(t, to.Format(time.RFC3339), req["until"])
+
+			w.WriteHeader(http.StatusAccepted)
+		})
+		defer mockServer.Close()
+
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command(
+			binPath,
+			"--format", "json",
+			"inbox", "export",
+			"--device-id", "device-1",
+			"--from", from.Format(time.RFC3339),
+			"--to", to.Format(time.RFC3339),
+		)
+		cmd.Env = append([]string{}, os.Environ()...)
+		cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+		cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		err := cmd.Run()
+		assert.NoError(t, err, "stderr: %s", stderr.String())
+		assert.Empty(t, stdout.String())
+	})
+
+	tests := []struct {
+		name   string
+		args   []string
+		stderr string
+	}{
+		{
+			name:   "invalid range",
+			args:   []string{"--device-id", "device-1", "--from", to.Format(time.RFC3339), "--to", from.Format(time.RFC3339)},
+			stderr: "From date must be less than or equal to To date",
+		},
+		{
+			name:   "missing device id",
+			args:   []string{"--from", from.Format(time.RFC3339), "--to", to.Format(time.RFC3339)},
+			stderr: `Required flag "device-id" not set`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			cmd := exec.Command(binPath, append([]string{"inbox", "export"}, tt.args...)...)
+			cmd.Env = append([]string{}, os.Environ()...)
+			cmd.Env = append(cmd.Env, "ASG_ENDPOINT=http://localhost:9999")
+			cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			err := cmd.Run()
+			assert.Error(t, err)
+			assert.Contains(t, stderr.String(), tt.stderr)
+		})
+	}
+}
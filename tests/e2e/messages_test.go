@@ -4,16 +4,21 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"e2e/testutils"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMessageSendValid(t *testing.T) {
@@ -195,6 +200,519 @@ func TestMessageSendValid(t *testing.T) {
 	}
 }
 
+func TestMessageSendFromFile(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	dir := t.TempDir()
+	textPath := filepath.Join(dir, "message.txt")
+	assert.NoError(t, os.WriteFile(textPath, []byte("Line 1\nLine 2\n"), 0o600))
+	dataPath := filepath.Join(dir, "payload.bin")
+	assert.NoError(t, os.WriteFile(dataPath, []byte{0x00, 0x01, 0xfe, 0xff}, 0o600))
+
+	tests := []struct {
+		name         string
+		args         []string
+		stdin        string
+		expectedText string
+		expectedData string
+	}{
+		{
+			name:         "text from file",
+			args:         []string{"--file", textPath},
+			expectedText: "Line 1\nLine 2",
+		},
+		{
+			name:         "text from stdin",
+			args:         []string{"-"},
+			stdin:        "Hello from stdin\n",
+			expectedText: "Hello from stdin",
+		},
+		{
+			name:         "binary data from file",
+			args:         []string{"--data", "--file", dataPath},
+			expectedData: "AAH+/w==",
+		},
+		{
+			name:         "binary data from stdin",
+			args:         []string{"--data", "-"},
+			stdin:        "hello world",
+			expectedData: "aGVsbG8gd29ybGQ=",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+				var req map[string]any
+				err := json.NewDecoder(r.Body).Decode(&req)
+				assert.NoError(t, err)
+
+				if tt.expectedData != "" {
+					dataMsg := req["dataMessage"].(map[string]interface{})
+					assert.Equal(t, tt.expectedData, dataMsg["data"])
+				} else {
+					textMsg := req["textMessage"].(map[string]interface{})
+					assert.Equal(t, tt.expectedText, textMsg["text"])
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(map[string]interface{}{"id": "msg-1", "state": "Pending"})
+			})
+			defer mockServer.Close()
+
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"send", "--phones", "+12025550123"}, tt.args...)
+
+			cmd := exec.Command(binPath, args...)
+			cmd.Env = append([]string{}, os.Environ()...)
+			cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+			cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+			cmd.Stdin = strings.NewReader(tt.stdin)
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			err := cmd.Run()
+			assert.NoError(t, err, "stderr: %s", stderr.String())
+		})
+	}
+}
+
+func TestMessageSendWait(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	tests := []struct {
+		name         string
+		args         []string
+		finalState   string
+		expectedCode int
+		expectedErr  string
+	}{
+		{
+			name:         "delivered",
+			args:         []string{"--wait"},
+			finalState:   "Delivered",
+			expectedCode: 0,
+		},
+		{
+			name:         "sent",
+			args:         []string{"--wait", "--until", "sent"},
+			finalState:   "Sent",
+			expectedCode: 0,
+		},
+		{
+			name:         "failed",
+			args:         []string{"--wait"},
+			finalState:   "Failed",
+			expectedCode: 5,
+			expectedErr:  "failed for one or more recipients",
+		},
+		{
+			name:         "timeout",
+			args:         []string{"--wait", "--wait-timeout", "1500ms"},
+			finalState:   "Pending",
+			expectedCode: 6,
+			expectedErr:  "Timeout waiting for message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+				state := "Pending"
+				if r.Method == http.MethodGet {
+					assert.Equal(t, "/messages/msg-1", r.URL.Path)
+					state = tt.finalState
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"id":    "msg-1",
+					"state": state,
+					"recipients": []map[string]interface{}{
+						{"phoneNumber": "+12025550123", "state": state},
+					},
+				})
+			})
+			defer mockServer.Close()
+
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"send", "--phones", "+12025550123"}, tt.args...)
+			args = append(args, "Hello, world!")
+
+			cmd := exec.Command(binPath, args...)
+			cmd.Env = append([]string{}, os.Environ()...)
+			cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+			cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			err := cmd.Run()
+			if tt.expectedCode == 0 {
+				assert.NoError(t, err, "stderr: %s", stderr.String())
+				assert.Contains(t, stdout.String(), "State: "+tt.finalState)
+				return
+			}
+
+			var exitErr *exec.ExitError
+			assert.ErrorAs(t, err, &exitErr)
+			assert.Equal(t, tt.expectedCode, exitErr.ExitCode())
+			assert.Contains(t, stderr.String(), tt.expectedErr)
+		})
+	}
+}
+
+func TestMessageSendTemplate(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	dir := t.TempDir()
+	templateFile := filepath.Join(dir, "template.txt")
+	require.NoError(t, os.WriteFile(templateFile, []byte("Hi {{.Name}}, order {{.Order}} is ready\n"), 0o600))
+
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(
+		"current: work\nprofiles:\n  work:\n    templates:\n      code: 'Your code is {{.Code}}'\n",
+	), 0o600))
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedText string
+		expectedErr  string
+	}{
+		{
+			name:         "inline template",
+			args:         []string{"--template", "Hello {{.FirstName}}, your code is {{.Code}}", "--var", "FirstName=Ann", "--var", "Code=1, 2, 3"},
+			expectedText: "Hello Ann, your code is 1, 2, 3",
+		},
+		{
+			name:         "template file",
+			args:         []string{"--template-file", templateFile, "--var", "Name=Bob", "--var", "Order=42"},
+			expectedText: "Hi Bob, order 42 is ready",
+		},
+		{
+			name:         "named template",
+			args:         []string{"--template-name", "code", "--var", "Code=987"},
+			expectedText: "Your code is 987",
+		},
+		{
+			name:        "missing variables",
+			args:        []string{"--template", "Hello {{.FirstName}}, your code is {{.Code}}"},
+			expectedErr: "missing template variables: Code, FirstName",
+		},
+		{
+			name:        "unknown named template",
+			args:        []string{"--template-name", "unknown"},
+			expectedErr: `template "unknown" not found`,
+		},
+		{
+			name:        "template with content argument",
+			args:        []string{"--template", "Hello", "content"},
+			expectedErr: "can't be combined with content argument",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var text string
+			mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+				var req map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&req)
+				if msg, ok := req["textMessage"].(map[string]interface{}); ok {
+					text, _ = msg["text"].(string)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(map[string]interface{}{"id": "msg-1", "state": "Pending"})
+			})
+			defer mockServer.Close()
+
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"--config", configFile, "send", "--phones", "+12025550123"}, tt.args...)
+
+			cmd := exec.Command(binPath, args...)
+			cmd.Env = append([]string{}, os.Environ()...)
+			cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+			cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			err := cmd.Run()
+			if tt.expectedErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, stderr.String(), tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err, "stderr: %s", stderr.String())
+			assert.Equal(t, tt.expectedText, text)
+		})
+	}
+}
+
+func TestMessageSendDefaultRegion(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedPhones []interface{}
+		expectedErr    string
+	}{
+		{
+			name:           "national numbers",
+			args:           []string{"--default-region", "US", "--phones", "(202) 555-0123", "--phones", "1 202 555 0124"},
+			expectedPhones: []interface{}{"+12025550123", "+12025550124"},
+		},
+		{
+			name:           "international numbers",
+			args:           []string{"--phones", "+7 (916) 123-45-67"},
+			expectedPhones: []interface{}{"+79161234567"},
+		},
+		{
+			name:           "skip phone validation",
+			args:           []string{"--default-region", "US", "--skip-phone-validation", "--phones", "+1 555-0123"},
+			expectedPhones: []interface{}{"+1 555-0123"},
+		},
+		{
+			name:        "impossible number",
+			args:        []string{"--default-region", "US", "--phones", "555-0123"},
+			expectedErr: `invalid phone number "555-0123": not a possible number for region US`,
+		},
+		{
+			name:        "unknown region",
+			args:        []string{"--default-region", "XX", "--phones", "+12025550123"},
+			expectedErr: `unknown region "XX"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var phones []interface{}
+			mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+				var req map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&req)
+				phones, _ = req["phoneNumbers"].([]interface{})
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(map[string]interface{}{"id": "msg-1", "state": "Pending"})
+			})
+			defer mockServer.Close()
+
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"send"}, tt.args...)
+			args = append(args, "Hello")
+
+			cmd := exec.Command(binPath, args...)
+			cmd.Env = append([]string{}, os.Environ()...)
+			cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+			cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			err := cmd.Run()
+			if tt.expectedErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, stderr.String(), tt.expectedErr)
+				assert.Nil(t, phones)
+				return
+			}
+
+			assert.NoError(t, err, "stderr: %s", stderr.String())
+			assert.Equal(t, tt.expectedPhones, phones)
+		})
+	}
+}
+
+func TestMessageSendProfileSkipsPhoneValidation(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	var phones []interface{}
+	mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		phones, _ = req["phoneNumbers"].([]interface{})
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "msg-1", "state": "Pending"})
+	})
+	defer mockServer.Close()
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	run := func(args ...string) (string, error) {
+		var stderr bytes.Buffer
+		cmd := exec.Command(binPath, append([]string{"--config", configFile}, args...)...)
+		cmd.Env = append([]string{}, os.Environ()...)
+		cmd.Stderr = &stderr
+
+		err := cmd.Run()
+		return stderr.String(), err
+	}
+
+	stderr, err := run(
+		"config", "set",
+		"--endpoint", mockServer.URL, "--username", "testuser", "--password", "testpass",
+		"--skip-phone-validation",
+		"main",
+	)
+	require.NoError(t, err, "stderr: %s", stderr)
+
+	// the profile default keeps the number as is, although the region can't parse it
+	stderr, err = run("--profile", "main", "send", "--default-region", "US", "--phones", "+1 555-0123", "Hello")
+	require.NoError(t, err, "stderr: %s", stderr)
+	assert.Equal(t, []interface{}{"+1 555-0123"}, phones)
+}
+
+func TestMessageSendEncrypted(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	var (
+		body      string
+		encrypted string
+	)
+	mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			b, _ := io.ReadAll(r.Body)
+			body = string(b)
+
+			var req map[string]interface{}
+			json.Unmarshal(b, &req)
+			assert.Equal(t, true, req["isEncrypted"])
+			if phones, ok := req["phoneNumbers"].([]interface{}); ok && len(phones) == 1 {
+				encrypted, _ = phones[0].(string)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":          "msg-1",
+			"state":       "Pending",
+			"isEncrypted": true,
+			"recipients": []map[string]interface{}{
+				{"phoneNumber": encrypted, "state": "Pending"},
+			},
+		})
+	})
+	defer mockServer.Close()
+
+	run := func(env []string, args ...string) (string, string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command(binPath, args...)
+		cmd.Env = append([]string{}, os.Environ()...)
+		cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+		cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+		cmd.Env = append(cmd.Env, env...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		err := cmd.Run()
+		return stdout.String(), stderr.String(), err
+	}
+
+	stdout, stderr, err := run(nil, "send", "--phones", "+12025550123", "--passphrase", "secret", "Top secret")
+	require.NoError(t, err, "stderr: %s", stderr)
+	assert.NotContains(t, body, "+12025550123")
+	assert.NotContains(t, body, "Top secret")
+	assert.Contains(t, body, "$aes-256-cbc/pbkdf2-sha1$")
+	assert.Contains(t, stdout, "+12025550123")
+
+	stdout, stderr, err = run([]string{"ASG_PASSPHRASE=secret"}, "status", "msg-1")
+	require.NoError(t, err, "stderr: %s", stderr)
+	assert.Contains(t, stdout, "+12025550123")
+
+	stdout, _, err = run(nil, "status", "msg-1")
+	require.NoError(t, err)
+	assert.NotContains(t, stdout, "+12025550123")
+
+	_, stderr, err = run([]string{"ASG_PASSPHRASE=wrong"}, "status", "msg-1")
+	assert.Error(t, err)
+	assert.Contains(t, stderr, "msg-1")
+}
+
+func TestMessageSendRetries(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	// run returns the message IDs of the requests in order
+	run := func(t *testing.T, statuses []int, args ...string) ([]string, string, error) {
+		t.Helper()
+
+		var ids []string
+		mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				ID string `json:"id"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			ids = append(ids, req.ID)
+
+			status := statuses[min(len(ids), len(statuses))-1]
+			if status != http.StatusAccepted {
+				w.WriteHeader(status)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "msg-1", "state": "Pending"})
+		})
+		defer mockServer.Close()
+
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command(binPath, append([]string{"send", "--phones", "+12025550123"}, args...)...)
+		cmd.Env = append([]string{}, os.Environ()...)
+		cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+		cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		err := cmd.Run()
+		return ids, stderr.String(), err
+	}
+
+	t.Run("transient errors are retried", func(t *testing.T) {
+		ids, stderr, err := run(
+			t,
+			[]int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusAccepted},
+			"--retries", "3", "--retry-backoff", "10ms", "Hello",
+		)
+		require.NoError(t, err, "stderr: %s", stderr)
+		assert.Len(t, ids, 3)
+		// the generated ID is kept for every attempt
+		assert.NotEmpty(t, ids[0])
+		assert.Equal(t, []string{ids[0], ids[0], ids[0]}, ids)
+		assert.Contains(t, stderr, "Attempts: 3")
+	})
+
+	t.Run("validation errors are not retried", func(t *testing.T) {
+		ids, stderr, err := run(
+			t,
+			[]int{http.StatusBadRequest},
+			"--retries", "3", "--retry-backoff", "10ms", "Hello",
+		)
+		require.Error(t, err)
+		assert.Len(t, ids, 1)
+		assert.Contains(t, stderr, "Attempts: 1")
+	})
+
+	t.Run("no retries by default", func(t *testing.T) {
+		ids, stderr, err := run(t, []int{http.StatusServiceUnavailable, http.StatusAccepted}, "Hello")
+		require.Error(t, err)
+		assert.Len(t, ids, 1)
+		assert.NotContains(t, stderr, "Attempts")
+	})
+
+	t.Run("invalid jitter", func(t *testing.T) {
+		ids, stderr, err := run(t, []int{http.StatusAccepted}, "--retry-jitter", "2", "Hello")
+		require.Error(t, err)
+		assert.Empty(t, ids)
+		assert.Contains(t, stderr, "retry jitter must be between 0 and 1")
+	})
+}
+
 func TestMessageSendInvalid(t *testing.T) {
 	binPath := testutils.RequireBinPath(t)
 
@@ -251,6 +769,30 @@ func TestMessageSendInvalid(t *testing.T) {
 			expectedErr: "Invalid base64 data",
 			setupStatus: http.StatusBadRequest,
 		},
+		{
+			name:        "both content argument and file",
+			message:     "Hello, world!",
+			phones:      []string{"+12025550123"},
+			extraArgs:   []string{"--file", "message.txt"},
+			expectedErr: "Message content argument and --file are mutually exclusive",
+			setupStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "invalid wait target",
+			message:     "Hello, world!",
+			phones:      []string{"+12025550123"},
+			extraArgs:   []string{"--wait", "--until", "read"},
+			expectedErr: "Until must be one of: sent, delivered",
+			setupStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "wait until delivered without delivery report",
+			message:     "Hello, world!",
+			phones:      []string{"+12025550123"},
+			extraArgs:   []string{"--wait", "--delivery-report=false"},
+			expectedErr: "Waiting until delivered requires delivery reports",
+			setupStatus: http.StatusBadRequest,
+		},
 		{
 			name:        "invalid data port",
 			message:     "SGVsbG8=", // valid base64
@@ -433,6 +975,349 @@ func TestMessageStatusInvalid(t *testing.T) {
 	}
 }
 
+func TestMessageStatusMultiple(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":    strings.TrimPrefix(r.URL.Path, "/messages/"),
+			"state": "Delivered",
+		})
+	})
+	defer mockServer.Close()
+
+	idsFile := filepath.Join(t.TempDir(), "ids.txt")
+	require.NoError(t, os.WriteFile(idsFile, []byte("msg-2\n\n# comment\nmsg-3\nmsg-1\n"), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(binPath, "--format", "json", "status", "--ids-file", idsFile, "msg-1", "-")
+	cmd.Env = append([]string{}, os.Environ()...)
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+	cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+	cmd.Stdin = strings.NewReader("msg-4\n")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	require.NoError(t, err, "stderr: %s", stderr.String())
+
+	var states []map[string]interface{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &states))
+
+	ids := make([]string, 0, len(states))
+	for _, s := range states {
+		ids = append(ids, s["id"].(string))
+	}
+	assert.Equal(t, []string{"msg-1", "msg-4", "msg-2", "msg-3"}, ids)
+}
+
+func TestMessageStatusList(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":    strings.TrimPrefix(r.URL.Path, "/messages/"),
+			"state": "Delivered",
+		})
+	})
+	defer mockServer.Close()
+
+	idsFile := filepath.Join(t.TempDir(), "ids.txt")
+	require.NoError(t, os.WriteFile(idsFile, []byte("msg-1\n"), 0o600))
+
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		list  bool
+	}{
+		{name: "single argument", args: []string{"msg-1"}, list: false},
+		{name: "ids file", args: []string{"--ids-file", idsFile}, list: true},
+		{name: "stdin argument", args: []string{"-"}, stdin: "msg-1\n", list: true},
+		{name: "stdin ids file", args: []string{"--ids-file", "-"}, stdin: "msg-1\n", list: true},
+		{name: "repeated argument", args: []string{"msg-1", "msg-1"}, list: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			cmd := exec.Command(binPath, append([]string{"--format", "json", "status"}, tt.args...)...)
+			cmd.Env = append([]string{}, os.Environ()...)
+			cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+			cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+			cmd.Stdin = strings.NewReader(tt.stdin)
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			require.NoError(t, cmd.Run(), "stderr: %s", stderr.String())
+
+			out := strings.TrimSpace(stdout.String())
+			assert.Equal(t, tt.list, strings.HasPrefix(out, "["), out)
+		})
+	}
+}
+
+func TestMessageStatusStdinTwice(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(binPath, "status", "--ids-file", "-", "-")
+	cmd.Env = append([]string{}, os.Environ()...)
+	cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+	cmd.Stdin = strings.NewReader("msg-1\n")
+	cmd.Stderr = &stderr
+
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, cmd.Run(), &exitErr)
+	assert.Equal(t, 1, exitErr.ExitCode())
+	assert.Contains(t, stderr.String(), "stdin can be read only once")
+}
+
+func TestMessageStatusWatch(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	tests := []struct {
+		name            string
+		args            []string
+		finalState      string
+		expectedCode    int
+		expectedErr     string
+		expectedSummary string
+	}{
+		{
+			name:            "delivered",
+			finalState:      "Delivered",
+			expectedCode:    0,
+			expectedSummary: "2 message(s), 2 delivered, 0 sent, 0 failed, 0 pending",
+		},
+		{
+			name:            "sent",
+			args:            []string{"--until", "sent"},
+			finalState:      "Sent",
+			expectedCode:    0,
+			expectedSummary: "2 message(s), 0 delivered, 2 sent, 0 failed, 0 pending",
+		},
+		{
+			name:            "failed",
+			finalState:      "Failed",
+			expectedCode:    5,
+			expectedErr:     "2 recipient(s) failed",
+			expectedSummary: "2 message(s), 0 delivered, 0 sent, 2 failed, 0 pending",
+		},
+		{
+			name:            "timeout",
+			args:            []string{"--watch-timeout", "1500ms"},
+			finalState:      "Pending",
+			expectedCode:    6,
+			expectedErr:     "Timeout waiting for messages",
+			expectedSummary: "2 message(s), 0 delivered, 0 sent, 0 failed, 2 pending",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu       sync.Mutex
+				requests = map[string]int{}
+			)
+			mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+				id := strings.TrimPrefix(r.URL.Path, "/messages/")
+
+				mu.Lock()
+				requests[id]++
+				state := "Pending"
+				if requests[id] > 1 {
+					state = tt.finalState
+				}
+				mu.Unlock()
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"id":    id,
+					"state": state,
+					"recipients": []map[string]interface{}{
+						{"phoneNumber": "+12025550123", "state": state},
+					},
+				})
+			})
+			defer mockServer.Close()
+
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"--format", "table", "status", "--watch"}, tt.args...)
+			args = append(args, "msg-1", "msg-2")
+
+			cmd := exec.Command(binPath, args...)
+			cmd.Env = append([]string{}, os.Environ()...)
+			cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+			cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			err := cmd.Run()
+			assert.Contains(t, stdout.String(), "RECIPIENT STATE")
+			assert.Contains(t, stdout.String(), "msg-2")
+			assert.Contains(t, stderr.String(), tt.expectedSummary)
+
+			if tt.expectedCode == 0 {
+				assert.NoError(t, err, "stderr: %s", stderr.String())
+				return
+			}
+
+			var exitErr *exec.ExitError
+			assert.ErrorAs(t, err, &exitErr)
+			assert.Equal(t, tt.expectedCode, exitErr.ExitCode())
+			assert.Contains(t, stderr.String(), tt.expectedErr)
+		})
+	}
+}
+
+func TestMessagesList(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	from := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC)
+
+	run := func(t *testing.T, handler http.HandlerFunc, args ...string) (string, string, error) {
+		t.Helper()
+
+		mockServer := testutils.CreateMockServer(handler)
+		t.Cleanup(mockServer.Close)
+
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command(binPath, args...)
+		cmd.Env = append([]string{}, os.Environ()...)
+		cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+		cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		err := cmd.Run()
+		return stdout.String(), stderr.String(), err
+	}
+
+	t.Run("filters and csv output", func(t *testing.T) {
+		stdout, stderr, err := run(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "/messages", r.URL.Path)
+
+			q := r.URL.Query()
+			assert.Equal(t, from.Format(time.RFC3339), q.Get("from"))
+			assert.Equal(t, to.Format(time.RFC3339), q.Get("to"))
+			assert.Equal(t, "Delivered", q.Get("state"))
+			assert.Equal(t, "device-1", q.Get("deviceId"))
+			assert.Equal(t, "10", q.Get("limit"))
+			assert.Equal(t, "20", q.Get("offset"))
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{
+				"id": "msg-1",
+				"deviceId": "device-1",
+				"state": "Delivered",
+				"recipients": [
+					{"phoneNumber": "+12025550123", "state": "Delivered"},
+					{"phoneNumber": "+12025550124", "state": "Failed", "error": "no service"}
+				]
+			}]`))
+		},
+			"--format", "csv",
+			"messages", "list",
+			"--from", from.Format(time.RFC3339),
+			"--to", to.Format(time.RFC3339),
+			"--state", "delivered",
+			"--device-id", "device-1",
+			"--limit", "10",
+			"--offset", "20",
+		)
+		require.NoError(t, err, "stderr: %s", stderr)
+		assert.Equal(t, strings.Join([]string{
+			"id,device_id,state,phone,recipient_state,error",
+			"msg-1,device-1,Delivered,+12025550123,Delivered,",
+			"msg-1,device-1,Delivered,+12025550124,Failed,no service",
+		}, "\n")+"\n", stdout)
+	})
+
+	t.Run("automatic paging", func(t *testing.T) {
+		var offsets []string
+		stdout, stderr, err := run(t, func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			assert.Equal(t, "100", q.Get("limit"))
+			offsets = append(offsets, q.Get("offset"))
+
+			count := 0
+			switch q.Get("offset") {
+			case "":
+				count = 100
+			case "100":
+				count = 1
+			}
+
+			page := make([]map[string]any, 0, count)
+			for range count {
+				page = append(page, map[string]any{"id": "msg", "state": "Sent"})
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(page)
+		}, "--format", "json", "messages", "list")
+		require.NoError(t, err, "stderr: %s", stderr)
+
+		var states []map[string]any
+		require.NoError(t, json.Unmarshal([]byte(stdout), &states))
+		assert.Len(t, states, 101)
+		assert.Equal(t, []string{"", "100", "101"}, offsets)
+		assert.NotContains(t, stderr, "Warning")
+	})
+
+	t.Run("capped page size", func(t *testing.T) {
+		var offsets []string
+		stdout, stderr, err := run(t, func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			offsets = append(offsets, q.Get("offset"))
+
+			// the server returns at most 50 of 120 messages per page
+			offset, _ := strconv.Atoi(q.Get("offset"))
+			page := make([]map[string]any, 0, 50)
+			for i := offset; i < min(offset+50, 120); i++ {
+				page = append(page, map[string]any{"id": fmt.Sprintf("msg-%d", i), "state": "Sent"})
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(page)
+		}, "--format", "json", "messages", "list")
+		require.NoError(t, err, "stderr: %s", stderr)
+
+		var states []map[string]any
+		require.NoError(t, json.Unmarshal([]byte(stdout), &states))
+		assert.Len(t, states, 120)
+		assert.Equal(t, []string{"", "50", "100", "120"}, offsets)
+		assert.Contains(t, stderr, "Warning: the server returns fewer than 100 messages per page")
+	})
+
+	t.Run("empty result", func(t *testing.T) {
+		stdout, stderr, err := run(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[]`))
+		}, "--format", "json", "messages", "list", "--limit", "5")
+		require.NoError(t, err, "stderr: %s", stderr)
+		assert.JSONEq(t, `[]`, stdout)
+	})
+
+	t.Run("invalid state", func(t *testing.T) {
+		_, stderr, err := run(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		}, "messages", "list", "--state", "unknown")
+		require.Error(t, err)
+		assert.Contains(t, stderr, "State must be one of: Pending, Processed, Sent, Delivered, Failed")
+	})
+}
+
 func TestMessageCommandHelp(t *testing.T) {
 	binPath := testutils.RequireBinPath(t)
 
@@ -451,7 +1336,7 @@ func TestMessageCommandHelp(t *testing.T) {
 		{
 			name:        "status command help",
 			args:        []string{"status", "--help"},
-			expectHelp:  true,
+			expectHelp:  false,
 			expectUsage: true,
 		},
 	}
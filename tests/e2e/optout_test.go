@@ -0,0 +1,113 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"e2e/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptOut(t *testing.T) {
+	binPath := testutils.RequireBinPath(t)
+
+	var (
+		mu     sync.Mutex
+		phones []string
+	)
+	mockServer := testutils.CreateMockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID           string   `json:"id"`
+			PhoneNumbers []string `json:"phoneNumbers"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		phones = append(phones, req.PhoneNumbers...)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": req.ID, "state": "Pending"})
+	})
+	defer mockServer.Close()
+
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	importFile := filepath.Join(dir, "unsubscribed.csv")
+	require.NoError(t, os.WriteFile(importFile, []byte("Name,Mobile\nEve,+1 202 555 0125\n"), 0o600))
+
+	run := func(stdin string, args ...string) (string, string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command(binPath, append([]string{"--config", configFile}, args...)...)
+		// the opt-out commands are local, only the batch send passes credentials
+		for _, item := range os.Environ() {
+			if !strings.HasPrefix(item, "ASG_") {
+				cmd.Env = append(cmd.Env, item)
+			}
+		}
+		cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+		cmd.Stdin = strings.NewReader(stdin)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		err := cmd.Run()
+		return stdout.String(), stderr.String(), err
+	}
+
+	_, stderr, err := run("", "optout", "add", "(202) 555-0124")
+	require.Error(t, err)
+	assert.Contains(t, stderr, "not in the international format")
+
+	_, stderr, err = run("", "optout", "add", "--default-region", "US", "--reason", "STOP", "(202) 555-0124")
+	require.NoError(t, err, "stderr: %s", stderr)
+	assert.Contains(t, stderr, "Added 1 numbers")
+
+	_, stderr, err = run("", "optout", "import", "--column", "mobile", importFile)
+	require.NoError(t, err, "stderr: %s", stderr)
+	assert.Contains(t, stderr, "Imported 1 numbers")
+
+	stdout, stderr, err := run("", "optout", "list")
+	require.NoError(t, err, "stderr: %s", stderr)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "+12025550124\t"))
+	assert.True(t, strings.HasSuffix(lines[0], "\tSTOP"))
+	assert.True(t, strings.HasPrefix(lines[1], "+12025550125\t"))
+
+	_, stderr, err = run(
+		"{\"phone\":\"+12025550123\",\"text\":\"Hello\"}\n"+
+			"{\"phone\":\"+12025550124\",\"text\":\"Hello\"}\n"+
+			"{\"phone\":\"+12025550125\",\"text\":\"Hello\"}\n",
+		"-u", "testuser", "-p", "testpass",
+		"batch", "send", "--input-format", "ndjson", "--map", "phone=phone,text=text", "-",
+	)
+	require.NoError(t, err, "stderr: %s", stderr)
+	assert.Contains(t, stderr, "[2] dropped: suppressed: +12025550124 opted out")
+	assert.Contains(t, stderr, "total=3 enqueued=1 failed=0 skipped=0 resumed=0 dropped=2")
+
+	mu.Lock()
+	assert.Equal(t, []string{"+12025550123"}, phones)
+	mu.Unlock()
+
+	_, stderr, err = run("", "optout", "remove", "+12025550124", "+12025550125")
+	require.NoError(t, err, "stderr: %s", stderr)
+	assert.Contains(t, stderr, "Removed 2 numbers")
+
+	stdout, _, err = run("", "optout", "list")
+	require.NoError(t, err)
+	assert.NotContains(t, stdout, "+1202555012")
+}
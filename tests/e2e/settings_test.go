@@ -0,0 +1,174 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"e2e/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const currentSettings = `{
+	"messages": {"limit_period": "PerDay", "limit_value": 100, "send_interval_min": 5},
+	"webhooks": {"retry_count": 3}
+}`
+
+func runSettings(t *testing.T, handler http.HandlerFunc, stdin string, args ...string) (string, string, error) {
+	t.Helper()
+
+	mockServer := testutils.CreateMockServer(handler)
+	t.Cleanup(mockServer.Close)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(testutils.RequireBinPath(t), args...)
+	cmd.Env = append([]string{}, os.Environ()...)
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ASG_ENDPOINT=%s", mockServer.URL))
+	cmd.Env = append(cmd.Env, "ASG_USERNAME=testuser", "ASG_PASSWORD=testpass")
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestSettingsGet(t *testing.T) {
+	tests := []struct {
+		format   string
+		contains []string
+	}{
+		{format: "text", contains: []string{"messages.limit_value: 100", "webhooks.retry_count: 3"}},
+		{format: "table", contains: []string{"KEY", "VALUE", "messages.limit_period", "PerDay"}},
+		{format: "json", contains: []string{`"limit_value": 100`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			stdout, stderr, err := runSettings(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/settings", r.URL.Path)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(currentSettings))
+			}, "", "--format", tt.format, "settings", "get")
+			require.NoError(t, err, "stderr: %s", stderr)
+
+			for _, s := range tt.contains {
+				assert.Contains(t, stdout, s)
+			}
+		})
+	}
+}
+
+func TestSettingsUpdate(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "settings.yaml")
+	require.NoError(t, os.WriteFile(yamlFile, []byte("messages:\n  limit_value: 200\n  send_interval_min: 5\nlogs:\n  lifetime_days: 30\n"), 0o600))
+	invalidFile := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("messages:\n  limit_valeu: 200\n"), 0o600))
+
+	tests := []struct {
+		name           string
+		args           []string
+		stdin          string
+		expectedMethod string
+		expectedBody   map[string]interface{}
+		contains       []string
+		notContains    []string
+		expectedErr    string
+	}{
+		{
+			name:           "patch from yaml",
+			args:           []string{"settings", "patch", "--file", yamlFile},
+			expectedMethod: http.MethodPatch,
+			expectedBody: map[string]interface{}{
+				"messages": map[string]interface{}{"limit_value": float64(200), "send_interval_min": float64(5)},
+				"logs":     map[string]interface{}{"lifetime_days": float64(30)},
+			},
+		},
+		{
+			name:           "replace from json stdin",
+			args:           []string{"settings", "replace", "--file", "-"},
+			stdin:          `{"webhooks": {"retry_count": 5}}`,
+			expectedMethod: http.MethodPut,
+			expectedBody: map[string]interface{}{
+				"webhooks": map[string]interface{}{"retry_count": float64(5)},
+			},
+		},
+		{
+			name: "patch diff",
+			args: []string{"settings", "patch", "--diff", "--file", yamlFile},
+			contains: []string{
+				"~ messages.limit_value: 100 -> 200",
+				"+ logs.lifetime_days: 30",
+			},
+			notContains: []string{"send_interval_min", "- webhooks.retry_count"},
+		},
+		{
+			name:  "replace diff",
+			args:  []string{"settings", "replace", "--diff", "--file", "-"},
+			stdin: `{"webhooks": {"retry_count": 3}}`,
+			contains: []string{
+				"- messages.limit_period: PerDay",
+				"- messages.limit_value: 100",
+			},
+			notContains: []string{"webhooks.retry_count"},
+		},
+		{
+			name:     "no changes",
+			args:     []string{"settings", "patch", "--diff", "--file", "-"},
+			stdin:    `{"webhooks": {"retry_count": 3}}`,
+			contains: []string{"No changes"},
+		},
+		{
+			name:        "unknown key",
+			args:        []string{"settings", "patch", "--file", invalidFile},
+			expectedErr: `unknown field "limit_valeu"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, err := runSettings(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				if r.Method == http.MethodGet {
+					w.Write([]byte(currentSettings))
+					return
+				}
+
+				assert.Equal(t, tt.expectedMethod, r.Method)
+				b, _ := io.ReadAll(r.Body)
+				var body map[string]interface{}
+				assert.NoError(t, json.Unmarshal(b, &body))
+				assert.Equal(t, tt.expectedBody, body)
+
+				w.Write(b)
+			}, tt.stdin, tt.args...)
+
+			if tt.expectedErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, stderr, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err, "stderr: %s", stderr)
+			for _, s := range tt.contains {
+				assert.Contains(t, stdout, s)
+			}
+			for _, s := range tt.notContains {
+				assert.NotContains(t, stdout, s)
+			}
+		})
+	}
+}